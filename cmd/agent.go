@@ -0,0 +1,469 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/config"
+	"github.com/KeitaShimura/logs-collector-client/internal/container"
+	"github.com/KeitaShimura/logs-collector-client/internal/ingest"
+	"github.com/KeitaShimura/logs-collector-client/internal/logger"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	"github.com/KeitaShimura/logs-collector-client/internal/multiline"
+	"github.com/KeitaShimura/logs-collector-client/internal/parser"
+	"github.com/KeitaShimura/logs-collector-client/internal/syslog"
+	"github.com/KeitaShimura/logs-collector-client/internal/tail"
+)
+
+// ErrNoAgentInputs は、agent コマンドに監視対象のファイルも syslog の受信先も指定されていない場合のエラー
+var ErrNoAgentInputs = errors.New("at least one --path, --syslog-udp, --syslog-tcp or --syslog-unix is required")
+
+// agent コマンドの既定値
+const (
+	defaultStateFile     = "logs-collector-agent.state.json"
+	defaultPollInterval  = time.Second
+	defaultSyslogService = "syslog"
+)
+
+// agentOptions は agent コマンドのフラグ値を保持する構造体
+type agentOptions struct {
+	transport    string
+	paths        stringListFlag
+	stateFile    string
+	pollInterval time.Duration
+	service      string
+	level        string
+	metadata     metadataFlag
+	parsing      parserOptions
+	parser       parser.Parser // parsing から生成した各行の解析に使用するパーサー
+	joining      multilineOptions
+	multiline    *multiline.Rule // joining から生成した複数行をまとめる規則
+	listen       syslog.Config   // syslog を受信するアドレス
+	container    string          // コンテナログの形式（空の場合はコンテナログとして扱わない）
+}
+
+// parseAgentFlags は agent コマンドの引数を解析する
+func parseAgentFlags(args []string) (*agentOptions, error) {
+	opts := &agentOptions{
+		transport:    "",
+		paths:        nil,
+		stateFile:    "",
+		pollInterval: 0,
+		service:      "",
+		level:        "",
+		metadata:     metadataFlag{},
+		parsing:      parserOptions{name: "", pattern: "", mapping: parser.Mapping{}},
+		parser:       nil,
+		joining:      multilineOptions{preset: "", start: "", continuation: "", maxLines: 0, maxBytes: 0, timeout: 0},
+		multiline:    nil,
+		listen:       syslog.Config{UDPAddr: "", TCPAddr: "", UnixPath: "", MaxMessageSize: 0, RetryInterval: 0},
+		container:    "",
+	}
+
+	flags := flag.NewFlagSet("agent", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.StringVar(&opts.transport, "transport", "", "通信方式（grpc|rest）。未指定時は TRANSPORT の値")
+	flags.Var(&opts.paths, "path", "監視対象ファイルのグロブパターン（複数指定可）")
+	flags.StringVar(&opts.stateFile, "state-file", defaultStateFile, "読み取り位置を保存する状態ファイル")
+	flags.DurationVar(&opts.pollInterval, "poll-interval", defaultPollInterval, "ファイルを確認する間隔")
+	flags.StringVar(&opts.service, "service", "", "サービス名（未指定時はファイル名、syslog では APP-NAME がない場合に syslog）")
+	flags.StringVar(&opts.level, "level", "INFO", "ログレベル")
+	flags.Var(opts.metadata, "meta", "メタデータ（key=value、複数指定可）")
+	flags.StringVar(&opts.listen.UDPAddr, "syslog-udp", "", "syslog を UDP で受信するアドレス（例: :514）")
+	flags.StringVar(&opts.listen.TCPAddr, "syslog-tcp", "", "syslog を TCP で受信するアドレス（例: :514）")
+	flags.StringVar(&opts.listen.UnixPath, "syslog-unix", "", "syslog を受信する Unix ドメインソケット（データグラムのみ。ストリームは非対応）のパス")
+	flags.StringVar(&opts.container, "container", "", "コンテナログの形式（auto|docker|cri）。指定時は各行をコンテナランタイムの記録として解析する")
+	opts.parsing.register(flags)
+	opts.joining.register(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return nil, fmt.Errorf("failed to parse agent flags: %w", err)
+	}
+
+	if opts.container != "" {
+		if err := container.ValidateFormat(opts.container); err != nil {
+			return nil, err //nolint:wrapcheck // 形式の一覧を含むエラーをそのまま返す
+		}
+	}
+
+	if len(opts.paths) == 0 && !opts.listen.Enabled() {
+		return nil, ErrNoAgentInputs
+	}
+
+	lineParser, err := opts.parsing.build()
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := opts.joining.build()
+	if err != nil {
+		return nil, err
+	}
+
+	opts.parser = lineParser
+	opts.multiline = rule
+
+	return opts, nil
+}
+
+// shipFunc は行（まとめた複数行を含む）をログに変換して送信する関数
+// record はコンテナログの行の場合のみ指定し、ストリームと時刻をログに設定する
+type shipFunc func(ctx context.Context, path, line string, record *container.Record) error
+
+// dropRejected は ship のうち、再試行しても回復しないエラーで送信できなかった行を警告を出力して破棄する shipFunc を返す
+// 破棄した行は nil を返してチェックポイントを進め、件数を dropped に加算する
+// 再試行で回復し得るエラー、停止によるエラー、設定の誤り（client.ErrInvalidClientConfig）はそのまま返す
+func dropRejected(ship shipFunc, dropped *atomic.Uint64, logger logger.Logger) shipFunc {
+	return func(ctx context.Context, path, line string, record *container.Record) error {
+		err := ship(ctx, path, line, record)
+		if err == nil || ctx.Err() != nil || errors.Is(err, client.ErrInvalidClientConfig) {
+			return err
+		}
+
+		if retryable, _ := client.IsRetryable(err); retryable {
+			return err
+		}
+
+		count := dropped.Add(1)
+		logger.Warn("dropping line that cannot be sent", "path", path, "dropped", count, "error", err.Error())
+
+		return nil
+	}
+}
+
+// lineHandler は読み取った行を ship で送信する tail.LineHandler を返す（空行は読み飛ばす）
+// 送信に失敗した行は次回のポーリングで再送される（破棄する行は dropRejected で nil を返す）
+// reassembler が nil でない場合は行をコンテナログの記録として解析し、分割された記録を連結してから送信する
+// group が nil でない場合は、ファイル（コンテナログではファイルとストリーム）ごとに複数行をまとめてから送信する
+// まとめている途中の行の位置は holdFunc でチェックポイントに反映する
+func (o *agentOptions) lineHandler(ship shipFunc, group *multiline.Group, reassembler *container.Reassembler) tail.LineHandler {
+	forward := func(ctx context.Context, path, line string, offset int64, record *container.Record) error {
+		if strings.TrimSpace(line) == "" {
+			return nil
+		}
+
+		if group != nil {
+			return group.Add(ctx, groupKey(path, record), line, offset) //nolint:wrapcheck // 送信のエラーは ship でラップ済み
+		}
+
+		return ship(ctx, path, line, record)
+	}
+
+	return func(ctx context.Context, path, line string, offset int64) error {
+		if reassembler == nil {
+			return forward(ctx, path, line, offset, nil)
+		}
+
+		// コンテナログの形式でない行は、そのまま 1 行として送信する
+		record, err := container.Parse(o.container, line)
+		if err != nil {
+			return forward(ctx, path, line, offset, nil)
+		}
+
+		// 連結した行の位置は先頭の記録の位置とし、連結中の記録より先にチェックポイントを進めない
+		joined, start, complete := reassembler.Join(path, record, offset)
+		if !complete {
+			return nil
+		}
+
+		if err := forward(ctx, path, joined.Message, start, &joined); err != nil {
+			return err
+		}
+
+		reassembler.Release(path, joined.Stream)
+
+		return nil
+	}
+}
+
+// holdFunc は、まとめている途中の複数行と連結している途中のコンテナログの記録のうち、
+// ファイルごとに最も前にある行の位置を返す tail.HoldFunc を返す
+// コンテナログではストリームごとにまとめるため、各ストリームのうち最も前の位置を返す
+func holdFunc(group *multiline.Group, reassembler *container.Reassembler) tail.HoldFunc {
+	if group == nil && reassembler == nil {
+		return nil
+	}
+
+	return func(path string) (int64, bool) {
+		var (
+			oldest int64
+			found  bool
+		)
+
+		hold := func(offset int64, ok bool) {
+			if ok && (!found || offset < oldest) {
+				oldest, found = offset, true
+			}
+		}
+
+		if group != nil {
+			for _, key := range []string{path, path + "\x00" + container.StreamStdout, path + "\x00" + container.StreamStderr} {
+				hold(group.Pending(key))
+			}
+		}
+
+		if reassembler != nil {
+			hold(reassembler.Pending(path))
+		}
+
+		return oldest, found
+	}
+}
+
+// groupKey は複数行をまとめる単位のキーを返す（コンテナログではストリームごとにまとめる）
+func groupKey(path string, record *container.Record) string {
+	if record == nil {
+		return path
+	}
+
+	return path + "\x00" + record.Stream
+}
+
+// splitGroupKey は groupKey のキーからファイルのパスと、コンテナログの場合はストリームのみを設定した記録を取り出す
+func splitGroupKey(key string) (string, *container.Record) {
+	path, stream, found := strings.Cut(key, "\x00")
+	if !found {
+		return path, nil
+	}
+
+	return path, &container.Record{Timestamp: time.Time{}, Stream: stream, Partial: false, Message: ""}
+}
+
+// shipLine は行（まとめた複数行を含む）をログに変換して送信する関数を返す
+// コンテナログの行には、ファイルのパスから取り出したコンテナの情報とストリームをメタデータとして付与する
+func (o *agentOptions) shipLine(cli client.Client, layouts []string) shipFunc {
+	return func(ctx context.Context, path, line string, record *container.Record) error {
+		tmpl := &ingest.Template{
+			Service:          o.service,
+			Level:            model.Level(o.level),
+			TraceID:          "",
+			Metadata:         o.metadata,
+			TimestampLayouts: layouts,
+			Parser:           o.parser,
+			Mapping:          o.parsing.mapping,
+			Multiline:        nil,
+		}
+
+		// コンテナログでは、行に時刻がない場合にコンテナランタイムが記録した時刻を使用する
+		now := time.Now()
+
+		var extra map[string]string
+
+		if record != nil {
+			extra = container.PathMetadata(path)
+			tmpl.Service = cmp.Or(tmpl.Service, container.ServiceName(extra))
+
+			if !record.Timestamp.IsZero() {
+				now = record.Timestamp
+			}
+		}
+
+		if tmpl.Service == "" {
+			tmpl.Service = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+
+		log := ingest.LineToLog(line, tmpl, now)
+		if log.Metadata == nil {
+			log.Metadata = map[string]string{}
+		}
+
+		log.Metadata["file"] = path
+		maps.Copy(log.Metadata, extra)
+
+		if record != nil {
+			log.Metadata[container.MetadataStream] = record.Stream
+		}
+
+		if err := cli.SendLog(ctx, log); err != nil {
+			return fmt.Errorf("failed to ship line from %s: %w", path, err)
+		}
+
+		return nil
+	}
+}
+
+// shipSyslog は受信した syslog メッセージをログに変換して送信する syslog.Handler を返す
+// メッセージは parser.SyslogParser で解析し、既定のマッピングで各項目に振り分ける（--parser / --field は使用しない）
+// cli は newSender の送信先のため、再試行や WAL への退避はファイルの行と同様に行われる
+// 再試行しても回復しないエラーは syslog.ErrRejected として返し、TCP でも再度渡されずに破棄される
+func (o *agentOptions) shipSyslog(cli client.Client) syslog.Handler {
+	tmpl := &ingest.Template{
+		Service:          cmp.Or(o.service, defaultSyslogService),
+		Level:            model.Level(o.level),
+		TraceID:          "",
+		Metadata:         o.metadata,
+		TimestampLayouts: nil, // SyslogParser のタイムスタンプは RFC 3339 形式のため既定のレイアウトで解釈する
+		Parser:           &parser.SyslogParser{Now: nil, Location: nil},
+		Mapping:          parser.Mapping{},
+		Multiline:        nil,
+	}
+
+	return func(ctx context.Context, msg syslog.Message) error {
+		log := ingest.LineToLog(msg.Data, tmpl, time.Now())
+		if log.Metadata == nil {
+			log.Metadata = map[string]string{}
+		}
+
+		log.Metadata["network"] = msg.Network
+		if msg.Remote != "" {
+			log.Metadata["remote_addr"] = msg.Remote
+		}
+
+		if err := cli.SendLog(ctx, log); err != nil {
+			if retryable, _ := client.IsRetryable(err); !retryable {
+				return fmt.Errorf("%w: %w", syslog.ErrRejected, err)
+			}
+
+			return fmt.Errorf("failed to ship syslog message: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// runAgent はファイルを監視し、追記された行と受信した syslog メッセージをログとして送信し続ける
+// SIGINT / SIGTERM を受け取るとチェックポイントを保存して終了する
+func runAgent(ctx context.Context, logger logger.Logger, loadOpts config.LoadOptions, args []string) int {
+	opts, err := parseAgentFlags(args)
+	if err != nil {
+		logger.Error("invalid arguments", err)
+
+		return 1
+	}
+
+	cfg, ok := loadConfig(logger, loadOpts)
+	if !ok {
+		return 1
+	}
+
+	// フラグの値で設定を上書きしてから検証する
+	cfg.Transport = cmp.Or(opts.transport, cfg.Transport)
+	if !validateConfig(logger, cfg) {
+		return 1
+	}
+
+	cli, ok := newSender(logger, cfg, opts.transport)
+	if !ok {
+		return 1
+	}
+	defer cli.Close()
+
+	var dropped atomic.Uint64
+
+	ship := dropRejected(opts.shipLine(cli, cfg.TimestampLayouts), &dropped, logger)
+
+	var group *multiline.Group
+	if opts.multiline != nil {
+		// タイムアウトで確定したイベントの送信の失敗は呼び出し元に返らないため、ここで記録する
+		group = multiline.NewGroup(*opts.multiline, func(ctx context.Context, key, event string) error {
+			path, record := splitGroupKey(key)
+
+			err := ship(ctx, path, event, record)
+			if err != nil {
+				logger.Warn("SendLog failed, will retry", "path", path, "error", err.Error())
+			}
+
+			return err
+		})
+	}
+
+	var reassembler *container.Reassembler
+	if opts.container != "" {
+		reassembler = container.NewReassembler(0)
+	}
+
+	var tailer *tail.Tailer
+	if len(opts.paths) > 0 {
+		tailer, err = tail.New(tail.Config{
+			Patterns:     opts.paths,
+			StateFile:    opts.stateFile,
+			PollInterval: opts.pollInterval,
+			Hold:         holdFunc(group, reassembler),
+			DrainPolls:   0,
+		}, opts.lineHandler(ship, group, reassembler), logger)
+		if err != nil {
+			logger.Error("failed to start agent", err)
+
+			return 1
+		}
+	}
+
+	var server *syslog.Server
+	if opts.listen.Enabled() {
+		server, err = syslog.Listen(opts.listen, opts.shipSyslog(cli), logger)
+		if err != nil {
+			logger.Error("failed to start syslog listener", err)
+
+			return 1
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("agent started", "transport", cfg.Transport, "paths", []string(opts.paths), "state_file", opts.stateFile)
+
+	var wg sync.WaitGroup
+
+	if server != nil {
+		logger.Info("syslog listener started", "udp", opts.listen.UDPAddr, "tcp", opts.listen.TCPAddr, "unix", opts.listen.UnixPath)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			server.Run(ctx)
+		}()
+	}
+
+	if tailer != nil {
+		tailer.Run(ctx)
+	} else {
+		<-ctx.Done()
+	}
+
+	wg.Wait()
+
+	if server != nil {
+		if dropped := server.Dropped(); dropped > 0 {
+			logger.Warn("syslog messages dropped", "dropped", dropped)
+		}
+	}
+
+	// まとめている途中の行は、停止のシグナルを受け取った後も送信を試みる
+	// 送信できなかった行はチェックポイントを進めず、次回の起動時に読み直す
+	if group != nil {
+		if err := group.Close(context.WithoutCancel(ctx)); err != nil {
+			logger.Error("failed to send buffered multiline events", err)
+		}
+	}
+
+	if dropped := dropped.Load(); dropped > 0 {
+		logger.Warn("lines dropped", "dropped", dropped)
+	}
+
+	if tailer != nil {
+		if err := tailer.Close(); err != nil {
+			logger.Error("failed to save checkpoints", err)
+
+			return 1
+		}
+	}
+
+	logger.Info("agent stopped")
+
+	return 0
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/container"
+	"github.com/KeitaShimura/logs-collector-client/internal/logger"
+	"github.com/KeitaShimura/logs-collector-client/internal/tail"
+)
+
+// TestDropRejected は再試行で回復しないエラーの行を破棄してチェックポイントを進め、
+// 回復し得るエラーの行と停止・設定の誤りによる失敗は読み直すことを検証する
+func TestDropRejected(t *testing.T) {
+	t.Parallel()
+
+	// sendError は code の client.Error を返す
+	sendError := func(code codes.Code, retryable bool) error {
+		return &client.Error{Transport: client.TransportGRPC, Operation: client.OperationSendLog, StatusCode: 0, Code: code, Message: "", Details: nil, Retryable: retryable, RetryAfter: 0, Err: errCause}
+	}
+
+	var (
+		shipped []string
+		failed  bool
+		dropped atomic.Uint64
+		opts    agentOptions
+	)
+
+	discard := logger.NewLogger(logger.WithWriter(io.Discard))
+	ship := dropRejected(func(_ context.Context, _, line string, _ *container.Record) error {
+		switch {
+		case line == "rejected":
+			return sendError(codes.InvalidArgument, false)
+		case line == "unavailable" && !failed:
+			failed = true
+
+			return sendError(codes.Unavailable, true)
+		}
+
+		shipped = append(shipped, line)
+
+		return nil
+	}, &dropped, discard)
+
+	// newTailer は dir の *.log を監視する Tailer を生成する
+	dir := t.TempDir()
+	newTailer := func() *tail.Tailer {
+		tailer, err := tail.New(tail.Config{
+			Patterns:     []string{filepath.Join(dir, "*.log")},
+			StateFile:    filepath.Join(dir, "state.json"),
+			PollInterval: time.Second,
+			Hold:         nil,
+			DrainPolls:   0,
+		}, opts.lineHandler(ship, nil, nil), discard)
+		require.NoError(t, err)
+
+		return tailer
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.log"), []byte("rejected\nok\nunavailable\nlast\n"), 0o600))
+
+	tailer := newTailer()
+	require.NoError(t, tailer.Poll(context.Background()))
+	require.Equal(t, []string{"ok"}, shipped)
+	require.Equal(t, uint64(1), dropped.Load())
+
+	require.NoError(t, tailer.Poll(context.Background()))
+	require.Equal(t, []string{"ok", "unavailable", "last"}, shipped)
+	require.NoError(t, tailer.Close())
+
+	// 破棄した行を含めてチェックポイントが進んでいるため、再起動後に読み直さない
+	restarted := newTailer()
+	require.NoError(t, restarted.Poll(context.Background()))
+	require.NoError(t, restarted.Close())
+	require.Equal(t, []string{"ok", "unavailable", "last"}, shipped)
+	require.Equal(t, uint64(1), dropped.Load())
+
+	// 停止による失敗は破棄せずにエラーを返す
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.Error(t, ship(ctx, "app.log", "rejected", nil))
+	require.Equal(t, uint64(1), dropped.Load())
+
+	// 設定の誤りは再試行しても回復しないが、設定を直せば送信できるため破棄しない
+	misconfigured := dropRejected(func(context.Context, string, string, *container.Record) error {
+		return fmt.Errorf("%w: %w", client.ErrInvalidClientConfig, errCause)
+	}, &dropped, discard)
+
+	require.ErrorIs(t, misconfigured(context.Background(), "app.log", "line", nil), client.ErrInvalidClientConfig)
+	require.Equal(t, uint64(1), dropped.Load())
+}
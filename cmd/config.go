@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/config"
+	"github.com/KeitaShimura/logs-collector-client/internal/logger"
+)
+
+// ErrInvalidConfigAction は、config コマンドに未対応のサブコマンドが指定された場合のエラー
+var ErrInvalidConfigAction = errors.New("usage: logs-collector-client config show|validate")
+
+// runConfig は設定に関するサブコマンドを実行する
+//   - show: 設定ファイル・環境変数・既定値から解決した設定を、秘匿情報を伏せて標準出力に書き出す
+//   - validate: 解決した設定を検証し、問題があればすべてを報告して終了コード 1 を返す
+func runConfig(logger logger.Logger, loadOpts config.LoadOptions, args []string) int {
+	if len(args) != 1 || (args[0] != "show" && args[0] != "validate") {
+		logger.Error("invalid arguments", fmt.Errorf("%w: %v", ErrInvalidConfigAction, args))
+
+		return 1
+	}
+
+	cfg, ok := loadConfig(logger, loadOpts)
+	if !ok {
+		return 1
+	}
+
+	if args[0] == "validate" {
+		if !validateConfig(logger, cfg) {
+			return 1
+		}
+
+		fmt.Fprintf(os.Stdout, "config is valid (%s)\n", cfg.Source())
+
+		return 0
+	}
+
+	if err := cfg.WriteMasked(os.Stdout); err != nil {
+		logger.Error("failed to show config", err)
+
+		return 1
+	}
+
+	return 0
+}
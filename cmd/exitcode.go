@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+)
+
+// 終了コード
+// ログ収集サーバーとの通信の失敗は、client.Error のステータスコードから種類ごとに異なる値を返す
+const (
+	exitFailure     = 1 // 引数・設定の誤りや入出力の失敗など、以下に該当しない失敗
+	exitUnavailable = 3 // 接続できない・タイムアウト・レート制限など、時間をおいて再実行すれば成功し得る失敗
+	exitRejected    = 4 // リクエストの内容がサーバーに拒否された（4xx / InvalidArgument など）
+	exitAuth        = 5 // 認証・認可の失敗（401・403 / Unauthenticated・PermissionDenied）
+	exitServer      = 6 // サーバー内部のエラー（500・501 / Internal・Unimplemented など）
+)
+
+// exitCode は err に対応する終了コードを返す
+func exitCode(err error) int {
+	clientErr := (*client.Error)(nil)
+	if !errors.As(err, &clientErr) || errors.Is(err, client.ErrInvalidClientConfig) {
+		return exitFailure
+	}
+
+	switch clientErr.Code { //nolint:exhaustive // 個別に扱わないコードはリクエストの拒否とみなす
+	case codes.OK, codes.Canceled:
+		return exitFailure
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return exitUnavailable
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return exitAuth
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+		return exitServer
+	default:
+		return exitRejected
+	}
+}
+
+// clientErrorArgs は err が client.Error の場合に、サーバーが返した内容を構造化ログの属性として返す
+func clientErrorArgs(err error) []any {
+	clientErr := (*client.Error)(nil)
+	if !errors.As(err, &clientErr) {
+		return nil
+	}
+
+	args := []any{"operation", clientErr.Operation, "code", clientErr.Code.String(), "retryable", clientErr.Retryable}
+
+	if clientErr.StatusCode != 0 {
+		args = append(args, "http_status", clientErr.StatusCode)
+	}
+
+	if clientErr.Message != "" {
+		args = append(args, "server_message", clientErr.Message)
+	}
+
+	if len(clientErr.Details) > 0 {
+		args = append(args, "details", clientErr.Details)
+	}
+
+	return args
+}
+
+// firstError は複数の送信の失敗のうち最初のエラーを保持する（終了コードの決定に使用する）
+type firstError struct {
+	mutex sync.Mutex
+	err   error
+}
+
+// record は最初のエラーのみを記録する
+func (f *firstError) record(err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.err == nil {
+		f.err = err
+	}
+}
+
+// exitCode は記録したエラーに対応する終了コードを返す
+func (f *firstError) exitCode() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return exitCode(f.err)
+}
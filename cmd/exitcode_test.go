@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+)
+
+// 共通エラー定義
+var errCause = errors.New("failed")
+
+// TestExitCode はエラーの種類ごとに対応する終了コードを返すことを検証する
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	// clientError は code の client.Error を返す
+	clientError := func(code codes.Code, err error) error {
+		return &client.Error{Transport: client.TransportREST, Operation: client.OperationSendLog, StatusCode: 0, Code: code, Message: "", Details: nil, Retryable: false, RetryAfter: 0, Err: err}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not a client error", errCause, exitFailure},
+		{"canceled", clientError(codes.Canceled, errCause), exitFailure},
+		{"invalid client config", clientError(codes.FailedPrecondition, fmt.Errorf("%w: %w", client.ErrInvalidClientConfig, errCause)), exitFailure},
+		{"unavailable", clientError(codes.Unavailable, errCause), exitUnavailable},
+		{"deadline exceeded", clientError(codes.DeadlineExceeded, errCause), exitUnavailable},
+		{"resource exhausted", clientError(codes.ResourceExhausted, errCause), exitUnavailable},
+		{"aborted", clientError(codes.Aborted, errCause), exitUnavailable},
+		{"invalid argument", clientError(codes.InvalidArgument, errCause), exitRejected},
+		{"not found", clientError(codes.NotFound, errCause), exitRejected},
+		{"failed precondition", clientError(codes.FailedPrecondition, client.ErrUnexpectedHTTPStatus), exitRejected},
+		{"unauthenticated", clientError(codes.Unauthenticated, errCause), exitAuth},
+		{"permission denied", fmt.Errorf("wrapped: %w", clientError(codes.PermissionDenied, errCause)), exitAuth},
+		{"internal", clientError(codes.Internal, errCause), exitServer},
+		{"unknown", clientError(codes.Unknown, errCause), exitServer},
+		{"data loss", clientError(codes.DataLoss, errCause), exitServer},
+		{"unimplemented", clientError(codes.Unimplemented, errCause), exitServer},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, test.want, exitCode(test.err))
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/config"
+	"github.com/KeitaShimura/logs-collector-client/internal/multiline"
+	"github.com/KeitaShimura/logs-collector-client/internal/parser"
+)
+
+// フラグに関するエラー
+var (
+	ErrInvalidMetadata  = errors.New("metadata must be in key=value form")
+	ErrMissingFlagValue = errors.New("flag needs an argument")
+	ErrParserOption     = errors.New("invalid parser option")
+	ErrMultilineOption  = errors.New("invalid multiline option")
+)
+
+// metadataFlag は --meta key=value を繰り返し指定するための flag.Value 実装
+type metadataFlag map[string]string
+
+// String は指定済みのメタデータを key=value のカンマ区切りで返す
+func (m metadataFlag) String() string {
+	pairs := make([]string, 0, len(m))
+	for key, value := range m {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// Set は key=value 形式の値を 1 件追加する
+func (m metadataFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidMetadata, value)
+	}
+
+	m[key] = val
+
+	return nil
+}
+
+// stringListFlag は同じフラグを繰り返し指定するための flag.Value 実装
+type stringListFlag []string
+
+// String は指定済みの値をカンマ区切りで返す
+func (l *stringListFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set は値を 1 件追加する
+func (l *stringListFlag) Set(value string) error {
+	*l = append(*l, value)
+
+	return nil
+}
+
+// extractGlobalFlags は全コマンド共通の --config / --profile を引数から取り除き、設定の読み込み元として返す
+// アクションの前後どちらに指定してもよい（"--" 以降は対象外）
+func extractGlobalFlags(args []string) (config.LoadOptions, []string, error) {
+	opts := config.LoadOptions{Path: "", Profile: ""}
+	rest := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i:]...)
+
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+
+		var target *string
+
+		switch {
+		case !strings.HasPrefix(arg, "-"):
+		case name == "config":
+			target = &opts.Path
+		case name == "profile":
+			target = &opts.Profile
+		}
+
+		if target == nil {
+			rest = append(rest, arg)
+
+			continue
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return opts, nil, fmt.Errorf("%w: %s", ErrMissingFlagValue, arg)
+			}
+
+			i++
+			value = args[i]
+		}
+
+		*target = value
+	}
+
+	return opts, rest, nil
+}
+
+// parserOptions は行の解析方法を指定するフラグ値を保持する構造体（send --stdin / agent で共通）
+type parserOptions struct {
+	name    string
+	pattern string
+	mapping parser.Mapping
+}
+
+// register は --parser / --pattern / --field を flags に登録する
+func (o *parserOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.name, "parser", "", "行の解析方法（json|logfmt|regex|syslog|common|combined）。未指定時は JSON の model.Log またはプレーンテキストとして扱う")
+	flags.StringVar(&o.pattern, "pattern", "", "--parser regex で使用する正規表現（名前付きグループがフィールド名になる）")
+	flags.Var(&o.mapping, "field", "フィールドのマッピング（target=key[,key...]、target は level|message|timestamp|trace_id|service、複数指定可）")
+}
+
+// build は指定されたパーサーを生成する（--parser 未指定の場合は nil）
+//
+//nolint:ireturn // parser.New の戻り値をそのまま返す
+func (o *parserOptions) build() (parser.Parser, error) {
+	if o.name == "" {
+		if o.pattern != "" {
+			return nil, fmt.Errorf("%w: --pattern requires --parser regex", ErrParserOption)
+		}
+
+		return nil, nil //nolint:nilnil // 未指定の場合はパーサーを使用しない
+	}
+
+	p, err := parser.New(o.name, o.pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParserOption, err)
+	}
+
+	return p, nil
+}
+
+// multilineOptions は複数行をまとめる規則を指定するフラグ値を保持する構造体（send --stdin / agent で共通）
+type multilineOptions struct {
+	preset       string
+	start        string
+	continuation string
+	maxLines     int
+	maxBytes     int
+	timeout      time.Duration
+}
+
+// register は --multiline / --multiline-* を flags に登録する
+func (o *multilineOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&o.preset, "multiline", "", "複数行をまとめる組み込みの規則（java|python|go）")
+	flags.StringVar(&o.start, "multiline-start", "", "イベントの開始行の正規表現")
+	flags.StringVar(&o.continuation, "multiline-continue", "", "直前のイベントに続く行の正規表現")
+	flags.IntVar(&o.maxLines, "multiline-max-lines", multiline.DefaultMaxLines, "1 イベントの最大行数")
+	flags.IntVar(&o.maxBytes, "multiline-max-bytes", multiline.DefaultMaxBytes, "1 イベントの最大バイト数")
+	flags.DurationVar(&o.timeout, "multiline-timeout", multiline.DefaultFlushTimeout, "最後の行からイベントを確定するまでの時間")
+}
+
+// build は指定された規則を生成する（いずれのパターンも未指定の場合は nil）
+func (o *multilineOptions) build() (*multiline.Rule, error) {
+	var (
+		rule *multiline.Rule
+		err  error
+	)
+
+	switch {
+	case o.preset != "" && (o.start != "" || o.continuation != ""):
+		return nil, fmt.Errorf("%w: --multiline cannot be combined with --multiline-start / --multiline-continue", ErrMultilineOption)
+	case o.preset != "":
+		rule, err = multiline.Preset(o.preset)
+	case o.start != "" || o.continuation != "":
+		rule, err = multiline.NewRule(o.start, o.continuation)
+	default:
+		return nil, nil //nolint:nilnil // 未指定の場合は 1 行を 1 件のログとする
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMultilineOption, err)
+	}
+
+	rule.MaxLines = o.maxLines
+	rule.MaxBytes = o.maxBytes
+	rule.FlushTimeout = o.timeout
+
+	return rule, nil
+}
@@ -4,27 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math"
 	"os"
-	"time"
-
-	"github.com/google/uuid"
 
 	"github.com/KeitaShimura/logs-collector-client/internal/client"
 	"github.com/KeitaShimura/logs-collector-client/internal/config"
 	"github.com/KeitaShimura/logs-collector-client/internal/logger"
-	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	"github.com/KeitaShimura/logs-collector-client/internal/wal"
 )
 
 // 共通エラー定義
 var (
 	ErrInvalidAction = errors.New("invalid action")
-	ErrIntOverflow   = errors.New("value overflows int32")
 )
 
-// os.Args の最低必要引数数（コマンド + アクション）
-const minArgs = 2
-
 func main() {
 	// run() の返り値（ステータスコード）を exit code として返す
 	os.Exit(run())
@@ -32,29 +24,47 @@ func main() {
 
 // run は CLI のメイン処理。引数に応じて対応する処理関数を呼び出す
 func run() int {
-	// 一時的なINFOレベルロガーを初期化
-	logger := logger.NewLogger(logger.WithLevel(logger.LevelInfo))
+	// 一時的なINFOレベルロガーを初期化（標準出力はコマンドの結果に使用するため、ログは標準エラー出力に書き出す）
+	logger := logger.NewLogger(logger.WithLevel(logger.LevelInfo), logger.WithWriter(os.Stderr))
 	ctx := context.Background()
 
+	// 全コマンド共通の --config / --profile を取り除く
+	loadOpts, rest, err := extractGlobalFlags(os.Args[1:])
+	if err != nil {
+		logger.Error("invalid arguments", err)
+
+		return 1
+	}
+
 	// 引数数チェック
-	if len(os.Args) < minArgs {
-		logger.Error("usage: go run cmd/main.go [grpc-send|grpc-get|rest-send|rest-get]", nil)
+	if len(rest) == 0 {
+		logger.Error("usage: logs-collector-client [--config file] [--profile name] [send|query|agent|config|grpc-send|grpc-get|rest-send|rest-get] [flags]", nil)
 
 		return 1
 	}
 
-	action := os.Args[1]
+	action := rest[0]
+	args := rest[1:]
 
 	// 入力されたアクションに応じた処理へルーティング
+	// grpc-* / rest-* は通信方式を固定した send / query として扱う
 	switch action {
+	case "send":
+		return runSend(ctx, logger, loadOpts, args)
+	case "query":
+		return runQuery(ctx, logger, loadOpts, args)
+	case "agent":
+		return runAgent(ctx, logger, loadOpts, args)
+	case "config":
+		return runConfig(logger, loadOpts, args)
 	case "grpc-send":
-		return runGRPCSend(ctx, logger)
+		return runSend(ctx, logger, loadOpts, append([]string{"--transport", client.TransportGRPC}, args...))
 	case "grpc-get":
-		return runGRPCGet(ctx, logger)
+		return runQuery(ctx, logger, loadOpts, append([]string{"--transport", client.TransportGRPC}, args...))
 	case "rest-send":
-		return runRESTSend(ctx, logger)
+		return runSend(ctx, logger, loadOpts, append([]string{"--transport", client.TransportREST}, args...))
 	case "rest-get":
-		return runRESTGet(ctx, logger)
+		return runQuery(ctx, logger, loadOpts, append([]string{"--transport", client.TransportREST}, args...))
 	default:
 		// 不正なアクションが指定された場合のエラーハンドリング
 		logger.Error("unknown action", fmt.Errorf("%w: %s", ErrInvalidAction, action))
@@ -63,222 +73,79 @@ func run() int {
 	}
 }
 
-// runGRPCSend は gRPC API を通じてログを送信する
-func runGRPCSend(ctx context.Context, logger logger.Logger) int {
-	// 環境変数から設定情報を読み込む
-	cfg, err := config.LoadConfig()
+// loadConfig は設定ファイルと環境変数から設定情報を読み込む
+func loadConfig(logger logger.Logger, opts config.LoadOptions) (*config.Config, bool) {
+	cfg, err := config.Load(opts)
 	if err != nil {
 		logger.Error("failed to load config", err)
 
-		return 1
-	}
-
-	// gRPC クライアントを初期化
-	client, err := client.NewGRPCClient(cfg.GRPCEndpoint)
-	if err != nil {
-		logger.Error("failed to connect to gRPC", err)
-
-		return 1
-	}
-	defer client.Close()
-
-	// テスト用ログを生成
-	log := &model.Log{
-		ID:        uuid.NewString(),
-		TraceID:   uuid.NewString(),
-		Timestamp: time.Now().Format(time.RFC3339),
-		Service:   "test-service",
-		Level:     "INFO",
-		Message:   "Hello, log world!",
-		Metadata:  map[string]string{"env": "dev"},
-	}
-
-	// gRPC API へログ送信を試みる
-	if err := client.SendLog(ctx, log); err != nil {
-		logger.Error("SendLog failed", err,
-			"id", log.ID,
-			"trace_id", log.TraceID,
-			"timestamp", log.Timestamp,
-			"service", log.Service,
-			"level", log.Level,
-			"message", log.Message,
-			"metadata", log.Metadata,
-		)
-
-		return 1
+		return nil, false
 	}
 
-	// 成功時は構造化ログで出力
-	logger.Info("SendLog succeeded",
-		"id", log.ID,
-		"trace_id", log.TraceID,
-		"timestamp", log.Timestamp,
-		"service", log.Service,
-		"level", log.Level,
-		"message", log.Message,
-		"metadata", log.Metadata,
-	)
-
-	return 0
+	return cfg, true
 }
 
-// runGRPCGet は gRPC API からログ一覧を取得し、ログ出力する
-func runGRPCGet(ctx context.Context, logger logger.Logger) int {
-	// 環境変数から設定情報を読み込む
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		logger.Error("failed to load config", err)
-
-		return 1
+// validateConfig は設定値を検証し、問題があればすべてをログ出力する
+func validateConfig(logger logger.Logger, cfg *config.Config) bool {
+	err := cfg.Validate()
+	if err == nil {
+		return true
 	}
 
-	// gRPC クライアントを初期化
-	client, err := client.NewGRPCClient(cfg.GRPCEndpoint)
-	if err != nil {
-		logger.Error("failed to connect to gRPC", err)
+	validationErr := (*config.ValidationError)(nil)
+	if !errors.As(err, &validationErr) {
+		logger.Error("invalid config", err)
 
-		return 1
+		return false
 	}
-	defer client.Close()
 
-	// DefaultLimit を int32 に変換（オーバーフローがないか安全にチェック）
-	limit, err := safeIntToInt32(cfg.DefaultLimit)
-	if err != nil {
-		logger.Error("invalid limit", err)
-
-		return 1
+	for _, problem := range validationErr.Problems {
+		logger.Error("invalid config", nil, "key", problem.Key, "value", problem.Value, "reason", problem.Reason)
 	}
 
-	// DefaultOffset を int32 に変換（オーバーフローがないか安全にチェック）
-	offset, err := safeIntToInt32(cfg.DefaultOffset)
-	if err != nil {
-		logger.Error("invalid offset", err)
-
-		return 1
-	}
-
-	// ログ取得（サービス・レベルでフィルタリング）
-	logs, err := client.GetLogs(ctx, "test-service", "INFO", limit, offset)
-	if err != nil {
-		logger.Error("GetLogs failed", err)
-
-		return 1
-	}
-
-	// 結果を構造化ログで出力
-	logger.Info("GetLogs succeeded", "count", len(logs))
-
-	for _, log := range logs {
-		logger.Info("Log entry", "id", log.ID, "message", log.Message)
-	}
-
-	return 0
+	return false
 }
 
-// runRESTSend は REST API を用いてログを送信する
-func runRESTSend(ctx context.Context, logger logger.Logger) int {
-	// 環境変数から設定情報を読み込む
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		logger.Error("failed to load config", err)
-
-		return 1
+// newClient は通信方式に応じたクライアントを生成する
+// transport が空でない場合は設定値を上書きする
+func newClient(logger logger.Logger, cfg *config.Config, transport string) (client.Client, bool) {
+	if transport != "" {
+		cfg.Transport = transport
 	}
 
-	// REST クライアント初期化
-	client := client.NewRESTClient(cfg.RESTEndpoint)
-
-	log := &model.Log{
-		ID:        uuid.NewString(),
-		TraceID:   uuid.NewString(),
-		Timestamp: time.Now().Format(time.RFC3339),
-		Service:   "test-service",
-		Level:     "INFO",
-		Message:   "Hello from REST!",
-		Metadata:  map[string]string{"env": "dev"},
-	}
-
-	// REST API へログを送信
-	if err := client.SendLog(ctx, log); err != nil {
-		logger.Error("SendLog (REST) failed", err,
-			"id", log.ID,
-			"trace_id", log.TraceID,
-			"timestamp", log.Timestamp,
-			"service", log.Service,
-			"level", log.Level,
-			"message", log.Message,
-			"metadata", log.Metadata,
-		)
-
-		return 1
-	}
-
-	// 成功時は構造化ログで出力
-	logger.Info("SendLog (REST) succeeded",
-		"id", log.ID,
-		"trace_id", log.TraceID,
-		"timestamp", log.Timestamp,
-		"service", log.Service,
-		"level", log.Level,
-		"message", log.Message,
-		"metadata", log.Metadata,
-	)
-
-	return 0
-}
-
-// runRESTGet は REST API からログ一覧を取得し、出力する
-func runRESTGet(ctx context.Context, logger logger.Logger) int {
-	// 環境変数から設定情報を読み込む
-	cfg, err := config.LoadConfig()
+	cli, err := client.New(cfg)
 	if err != nil {
-		logger.Error("failed to load config", err)
+		logger.Error("failed to create client", err, "transport", cfg.Transport)
 
-		return 1
+		return nil, false
 	}
 
-	// REST クライアント初期化
-	client := client.NewRESTClient(cfg.RESTEndpoint)
-
-	// DefaultLimit を int32 に変換（オーバーフローがないか安全にチェック）
-	limit, err := safeIntToInt32(cfg.DefaultLimit)
-	if err != nil {
-		logger.Error("invalid limit", err)
-
-		return 1
-	}
-
-	// DefaultOffset を int32 に変換（オーバーフローがないか安全にチェック）
-	offset, err := safeIntToInt32(cfg.DefaultOffset)
-	if err != nil {
-		logger.Error("invalid offset", err)
+	return cli, true
+}
 
-		return 1
+// newSender はログ送信用のクライアントを生成する
+// WAL_DIR が設定されている場合は、送信前にディスクへ書き込む wal.BufferedClient でラップする
+func newSender(logger logger.Logger, cfg *config.Config, transport string) (client.Client, bool) {
+	cli, ok := newClient(logger, cfg, transport)
+	if !ok || cfg.WALDir == "" {
+		return cli, ok
 	}
 
-	// ログ取得（サービス・レベルでフィルタリング）
-	logs, err := client.GetLogs(ctx, "test-service", "INFO", limit, offset)
+	queue, err := wal.Open(wal.Options{
+		Dir:          cfg.WALDir,
+		MaxBytes:     cfg.WALMaxBytes,
+		SegmentBytes: cfg.WALSegmentBytes,
+	})
 	if err != nil {
-		logger.Error("GetLogs (REST) failed", err)
-
-		return 1
-	}
-
-	// 結果を構造化ログで出力
-	logger.Info("GetLogs (REST) succeeded", "count", len(logs))
-
-	for _, log := range logs {
-		logger.Info("Log entry", "id", log.ID, "message", log.Message)
-	}
-
-	return 0
-}
+		logger.Error("failed to open WAL", err, "dir", cfg.WALDir)
+		cli.Close()
 
-// safeIntToInt32 は int 値を int32 に安全に変換する関数
-func safeIntToInt32(n int) (int32, error) {
-	if n > math.MaxInt32 || n < math.MinInt32 {
-		return 0, fmt.Errorf("%w: %d", ErrIntOverflow, n)
+		return nil, false
 	}
 
-	return int32(n), nil
+	return wal.NewBufferedClient(cli, queue, wal.ShipOptions{
+		BatchSize:     cfg.BatchMaxCount,
+		RetryInterval: cfg.WALRetryInterval,
+		DrainTimeout:  cfg.WALDrainTimeout,
+	}, logger), true
 }
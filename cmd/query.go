@@ -0,0 +1,329 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/config"
+	"github.com/KeitaShimura/logs-collector-client/internal/logger"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	"github.com/KeitaShimura/logs-collector-client/internal/output"
+)
+
+// query コマンドのエラー定義
+var (
+	ErrConflictingRange = errors.New("--since and --from cannot be used together")
+	ErrInvalidRange     = errors.New("--from must be before --to")
+	ErrInvalidTimeFlag  = errors.New("time must be RFC3339")
+	ErrFollowWithTo     = errors.New("--follow cannot be used with --to")
+	ErrFollowFormat     = errors.New("--follow supports only table, ndjson and raw output")
+	ErrFollowWithAll    = errors.New("--follow and --all cannot be used together")
+)
+
+// defaultFollowInterval は --follow で GetLogs を繰り返す間隔の既定値
+const defaultFollowInterval = 2 * time.Second
+
+// queryOptions は query コマンドのフラグ値を保持する構造体
+type queryOptions struct {
+	transport  string
+	service    string
+	level      string
+	since      time.Duration
+	from       string
+	to         string
+	limit      int
+	offset     int
+	output     string
+	follow     bool
+	interval   time.Duration
+	all        bool
+	pageSize   int
+	maxResults int
+}
+
+// parseQueryFlags は query コマンドの引数を解析する
+// limit / offset の既定値には設定値を用いる
+func parseQueryFlags(args []string, cfg *config.Config) (*queryOptions, error) {
+	opts := &queryOptions{
+		transport:  "",
+		service:    "",
+		level:      "",
+		since:      0,
+		from:       "",
+		to:         "",
+		limit:      0,
+		offset:     0,
+		output:     output.FormatTable,
+		follow:     false,
+		interval:   defaultFollowInterval,
+		all:        false,
+		pageSize:   0,
+		maxResults: 0,
+	}
+
+	flags := flag.NewFlagSet("query", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.StringVar(&opts.transport, "transport", "", "通信方式（grpc|rest）。未指定時は TRANSPORT の値")
+	flags.StringVar(&opts.service, "service", "", "サービス名で絞り込む")
+	flags.StringVar(&opts.level, "level", "", "ログレベルで絞り込む")
+	flags.DurationVar(&opts.since, "since", 0, "現在時刻からさかのぼる期間（例: 15m, 2h）")
+	flags.StringVar(&opts.from, "from", "", "取得範囲の開始時刻（RFC3339）")
+	flags.StringVar(&opts.to, "to", "", "取得範囲の終了時刻（RFC3339）")
+	flags.IntVar(&opts.limit, "limit", cfg.DefaultLimit, "取得件数の上限")
+	flags.IntVar(&opts.offset, "offset", cfg.DefaultOffset, "取得の開始位置")
+	flags.StringVar(&opts.output, "output", output.FormatTable, "出力形式（table|json|ndjson|csv|raw）")
+	flags.BoolVar(&opts.follow, "follow", false, "新しいログを取得し続ける（Ctrl+C で終了）")
+	flags.DurationVar(&opts.interval, "poll-interval", defaultFollowInterval, "--follow で取得を繰り返す間隔")
+	flags.BoolVar(&opts.all, "all", false, "ページを進めて条件に一致するログをすべて取得する（--limit は無視する）")
+	flags.IntVar(&opts.pageSize, "page-size", cfg.QueryPageSize, "--all で 1 回に取得する件数")
+	flags.IntVar(&opts.maxResults, "max-results", cfg.QueryMaxResults, "--all で取得する件数の上限（0 は無制限）")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, fmt.Errorf("failed to parse query flags: %w", err)
+	}
+
+	return opts, nil
+}
+
+// buildQuery はフラグ値から検索条件を組み立てる
+func (o *queryOptions) buildQuery(now time.Time) (*model.LogQuery, error) {
+	if o.since > 0 && o.from != "" {
+		return nil, ErrConflictingRange
+	}
+
+	if o.follow && o.to != "" {
+		return nil, ErrFollowWithTo
+	}
+
+	if o.follow && o.all {
+		return nil, ErrFollowWithAll
+	}
+
+	if o.follow && (o.output == output.FormatJSON || o.output == output.FormatCSV) {
+		return nil, ErrFollowFormat
+	}
+
+	query := &model.LogQuery{
+		Service:   o.service,
+		Level:     "",
+		StartTime: time.Time{},
+		EndTime:   time.Time{},
+		Limit:     int32(o.limit),  //nolint:gosec // applyTo で設定に反映し、Config.Validate で範囲を検証済み
+		Offset:    int32(o.offset), //nolint:gosec // 同上
+	}
+
+	// レベルは別名（warning など）も受け付け、正規のレベルで検索する
+	if o.level != "" {
+		level, err := model.ParseLevel(o.level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --level: %w", err)
+		}
+
+		query.Level = level
+	}
+
+	var err error
+
+	if o.since > 0 {
+		query.StartTime = now.Add(-o.since)
+	}
+
+	if query.StartTime, err = parseTimeFlag(o.from, query.StartTime); err != nil {
+		return nil, err
+	}
+
+	if query.EndTime, err = parseTimeFlag(o.to, query.EndTime); err != nil {
+		return nil, err
+	}
+
+	if !query.StartTime.IsZero() && !query.EndTime.IsZero() && !query.StartTime.Before(query.EndTime) {
+		return nil, ErrInvalidRange
+	}
+
+	return query, nil
+}
+
+// applyTo はフラグの値で設定を上書きする（フラグ > 環境変数 > 設定ファイル > 既定値）
+// limit / offset などの既定値は設定値のため、未指定の場合は設定値のまま変わらない
+func (o *queryOptions) applyTo(cfg *config.Config) {
+	cfg.Transport = cmp.Or(o.transport, cfg.Transport)
+	cfg.DefaultLimit = o.limit
+	cfg.DefaultOffset = o.offset
+	cfg.QueryPageSize = o.pageSize
+	cfg.QueryMaxResults = o.maxResults
+}
+
+// parseTimeFlag は RFC3339 のフラグ値を解析する。空文字列の場合は fallback を返す
+func parseTimeFlag(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidTimeFlag, value)
+	}
+
+	return parsed, nil
+}
+
+// runQuery はフラグで指定された条件でログ一覧を取得し、--output の形式で標準出力に書き出す
+func runQuery(ctx context.Context, logger logger.Logger, loadOpts config.LoadOptions, args []string) int {
+	cfg, ok := loadConfig(logger, loadOpts)
+	if !ok {
+		return 1
+	}
+
+	opts, err := parseQueryFlags(args, cfg)
+	if err != nil {
+		logger.Error("invalid arguments", err)
+
+		return 1
+	}
+
+	opts.applyTo(cfg)
+
+	if !validateConfig(logger, cfg) {
+		return 1
+	}
+
+	query, err := opts.buildQuery(time.Now())
+	if err != nil {
+		logger.Error("invalid query", err)
+
+		return 1
+	}
+
+	printer, err := output.New(opts.output, os.Stdout)
+	if err != nil {
+		logger.Error("invalid arguments", err)
+
+		return 1
+	}
+
+	cli, ok := newClient(logger, cfg, opts.transport)
+	if !ok {
+		return 1
+	}
+	defer cli.Close()
+
+	if opts.follow {
+		return followQuery(ctx, logger, cli, query, opts.interval, printer)
+	}
+
+	if opts.all {
+		return allQuery(ctx, logger, cli, query, opts, printer)
+	}
+
+	// ログ取得
+	logs, err := cli.GetLogs(ctx, query)
+	if err != nil {
+		logger.Error("GetLogs failed", err, append([]any{"transport", cfg.Transport}, clientErrorArgs(err)...)...)
+
+		return exitCode(err)
+	}
+
+	// 結果を標準出力に書き出し、件数は診断情報として標準エラー出力に記録する
+	if err := printer.Print(logs); err != nil {
+		logger.Error("failed to write logs", err)
+
+		return 1
+	}
+
+	if err := printer.Flush(); err != nil {
+		logger.Error("failed to write logs", err)
+
+		return 1
+	}
+
+	logger.Info("GetLogs succeeded", "transport", cfg.Transport, "count", len(logs))
+
+	return 0
+}
+
+// allQuery はページを進めながら条件に一致するログをすべて取得して出力する
+// --max-results を超えるログがある場合は、上限までを出力して警告する
+func allQuery(
+	ctx context.Context,
+	logger logger.Logger,
+	cli client.Client,
+	query *model.LogQuery,
+	opts *queryOptions,
+	printer output.Printer,
+) int {
+	pageOptions := client.PageOptions{
+		PageSize:   int32(opts.pageSize), //nolint:gosec // applyTo で設定に反映し、Config.Validate で範囲を検証済み
+		MaxResults: opts.maxResults,
+	}
+	count := 0
+
+	for log, err := range client.All(ctx, cli, *query, pageOptions) {
+		if errors.Is(err, client.ErrMaxResultsReached) {
+			logger.Warn("results truncated", "max_results", opts.maxResults)
+
+			break
+		}
+
+		if err != nil {
+			logger.Error("GetLogs failed", err, append([]any{"fetched", count}, clientErrorArgs(err)...)...)
+
+			return exitCode(err)
+		}
+
+		if err := printer.Print([]*model.Log{log}); err != nil {
+			logger.Error("failed to write logs", err)
+
+			return 1
+		}
+
+		count++
+	}
+
+	if err := printer.Flush(); err != nil {
+		logger.Error("failed to write logs", err)
+
+		return 1
+	}
+
+	logger.Info("GetLogs succeeded", "count", count)
+
+	return 0
+}
+
+// followQuery は中断されるまで新しいログを取得し、取得するたびに出力する
+func followQuery(
+	ctx context.Context,
+	logger logger.Logger,
+	cli client.Client,
+	query *model.LogQuery,
+	interval time.Duration,
+	printer output.Printer,
+) int {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := client.Follow(ctx, cli, *query, interval, func(logs []*model.Log) error {
+		if err := printer.Print(logs); err != nil {
+			return err //nolint:wrapcheck // 呼び出し元でログ出力する
+		}
+
+		return printer.Flush() //nolint:wrapcheck // 呼び出し元でログ出力する
+	}, func(err error) {
+		logger.Warn("GetLogs failed, will retry", "error", err.Error())
+	})
+	if err != nil {
+		logger.Error("failed to write logs", err)
+
+		return 1
+	}
+
+	return 0
+}
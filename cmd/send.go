@@ -0,0 +1,285 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/config"
+	"github.com/KeitaShimura/logs-collector-client/internal/ingest"
+	"github.com/KeitaShimura/logs-collector-client/internal/logger"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	"github.com/KeitaShimura/logs-collector-client/internal/multiline"
+	"github.com/KeitaShimura/logs-collector-client/internal/parser"
+)
+
+// sendOptions は send コマンドのフラグ値を保持する構造体
+type sendOptions struct {
+	transport string
+	service   string
+	level     string
+	message   string
+	traceID   string
+	timestamp string
+	metadata  metadataFlag
+	stdin     bool
+	batch     bool
+	parsing   parserOptions
+	parser    parser.Parser // parsing から生成した --stdin の各行の解析に使用するパーサー
+	joining   multilineOptions
+	multiline *multiline.Rule // joining から生成した --stdin の複数行をまとめる規則
+}
+
+// parseSendFlags は send コマンドの引数を解析する
+func parseSendFlags(args []string) (*sendOptions, error) {
+	opts := &sendOptions{
+		transport: "",
+		service:   "",
+		level:     "",
+		message:   "",
+		traceID:   "",
+		timestamp: "",
+		metadata:  metadataFlag{},
+		stdin:     false,
+		batch:     false,
+		parsing:   parserOptions{name: "", pattern: "", mapping: parser.Mapping{}},
+		parser:    nil,
+		joining:   multilineOptions{preset: "", start: "", continuation: "", maxLines: 0, maxBytes: 0, timeout: 0},
+		multiline: nil,
+	}
+
+	flags := flag.NewFlagSet("send", flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	flags.StringVar(&opts.transport, "transport", "", "通信方式（grpc|rest）。未指定時は TRANSPORT の値")
+	flags.StringVar(&opts.service, "service", "test-service", "サービス名")
+	flags.StringVar(&opts.level, "level", "INFO", "ログレベル")
+	flags.StringVar(&opts.message, "message", "Hello, log world!", "ログメッセージ")
+	flags.StringVar(&opts.traceID, "trace-id", "", "トレース ID（未指定時は自動生成）")
+	flags.StringVar(&opts.timestamp, "timestamp", "", "タイムスタンプ（RFC3339 / Unix 時間など TIMESTAMP_LAYOUTS の形式、未指定時は現在時刻）")
+	flags.Var(opts.metadata, "meta", "メタデータ（key=value、複数指定可）")
+	flags.BoolVar(&opts.stdin, "stdin", false, "標準入力の各行を 1 件のログとして EOF まで送信する")
+	flags.BoolVar(&opts.batch, "batch", false, "--stdin 時にログをまとめて送信する（BATCH_ENABLED でも有効化）")
+	opts.parsing.register(flags)
+	opts.joining.register(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return nil, fmt.Errorf("failed to parse send flags: %w", err)
+	}
+
+	lineParser, err := opts.parsing.build()
+	if err != nil {
+		return nil, err
+	}
+
+	if lineParser != nil && !opts.stdin {
+		return nil, fmt.Errorf("%w: --parser requires --stdin", ErrParserOption)
+	}
+
+	rule, err := opts.joining.build()
+	if err != nil {
+		return nil, err
+	}
+
+	if rule != nil && !opts.stdin {
+		return nil, fmt.Errorf("%w: --multiline requires --stdin", ErrMultilineOption)
+	}
+
+	opts.parser = lineParser
+	opts.multiline = rule
+
+	return opts, nil
+}
+
+// buildLog はフラグ値から送信する model.Log を組み立てる
+// 未指定の ID / TraceID / Timestamp は補完し、送信前に検証する（timestamp は layouts で解釈する）
+func (o *sendOptions) buildLog(now time.Time, layouts []string) (*model.Log, error) {
+	log := &model.Log{
+		ID:        "",
+		TraceID:   o.traceID,
+		Timestamp: time.Time{},
+		Level:     model.Level(o.level),
+		Service:   o.service,
+		Message:   o.message,
+		Metadata:  o.metadata,
+	}
+
+	if o.timestamp != "" {
+		timestamp, err := model.ParseTimestamp(o.timestamp, layouts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build log: %w", err)
+		}
+
+		log.Timestamp = timestamp
+	}
+
+	log.Normalize(now)
+
+	if err := log.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to build log: %w", err)
+	}
+
+	return log, nil
+}
+
+// template は標準入力の各行に適用する既定値を返す
+func (o *sendOptions) template(layouts []string) *ingest.Template {
+	return &ingest.Template{
+		Service:          o.service,
+		Level:            model.Level(o.level),
+		TraceID:          o.traceID,
+		Metadata:         o.metadata,
+		TimestampLayouts: layouts,
+		Parser:           o.parser,
+		Mapping:          o.parsing.mapping,
+		Multiline:        o.multiline,
+	}
+}
+
+// runSend はフラグで指定された内容のログを送信する
+func runSend(ctx context.Context, logger logger.Logger, loadOpts config.LoadOptions, args []string) int {
+	opts, err := parseSendFlags(args)
+	if err != nil {
+		logger.Error("invalid arguments", err)
+
+		return 1
+	}
+
+	if opts.stdin {
+		return runSendStdin(ctx, logger, loadOpts, opts)
+	}
+
+	cfg, ok := loadConfig(logger, loadOpts)
+	if !ok {
+		return 1
+	}
+
+	// フラグの値で設定を上書きしてから検証する
+	cfg.Transport = cmp.Or(opts.transport, cfg.Transport)
+	if !validateConfig(logger, cfg) {
+		return 1
+	}
+
+	log, err := opts.buildLog(time.Now(), cfg.TimestampLayouts)
+	if err != nil {
+		logger.Error("invalid log", err)
+
+		return 1
+	}
+
+	cli, ok := newSender(logger, cfg, opts.transport)
+	if !ok {
+		return 1
+	}
+	defer cli.Close()
+
+	// API へログ送信を試みる
+	if err := cli.SendLog(ctx, log); err != nil {
+		logger.Error("SendLog failed", err, append([]any{
+			"transport", cfg.Transport,
+			"id", log.ID,
+			"trace_id", log.TraceID,
+			"timestamp", log.Timestamp,
+			"service", log.Service,
+			"level", log.Level,
+			"message", log.Message,
+			"metadata", log.Metadata,
+		}, clientErrorArgs(err)...)...)
+
+		return exitCode(err)
+	}
+
+	// 成功時は構造化ログで出力
+	logger.Info("SendLog succeeded",
+		"transport", cfg.Transport,
+		"id", log.ID,
+		"trace_id", log.TraceID,
+		"timestamp", log.Timestamp,
+		"service", log.Service,
+		"level", log.Level,
+		"message", log.Message,
+		"metadata", log.Metadata,
+	)
+
+	return 0
+}
+
+// runSendStdin は標準入力から読み取った各行をログとして送信し、送信件数を集計する
+func runSendStdin(ctx context.Context, logger logger.Logger, loadOpts config.LoadOptions, opts *sendOptions) int {
+	cfg, ok := loadConfig(logger, loadOpts)
+	if !ok {
+		return 1
+	}
+
+	// フラグの値で設定を上書きしてから検証する
+	cfg.Transport = cmp.Or(opts.transport, cfg.Transport)
+	if !validateConfig(logger, cfg) {
+		return 1
+	}
+
+	cli, ok := newSender(logger, cfg, opts.transport)
+	if !ok {
+		return 1
+	}
+
+	// バッチ送信時の失敗件数（onError に渡された、送信に失敗したログの件数）
+	var batchFailed atomic.Int64
+
+	// 終了コードは最初に失敗した送信のエラーから決める
+	var failure firstError
+
+	// WAL 使用時は WAL からの転送がまとめて送信するため、メモリ上でのバッファは行わない
+	if (opts.batch || cfg.BatchEnabled) && cfg.WALDir == "" {
+		batching, err := client.NewBatchingClient(cli, client.BatchOptions{
+			MaxCount:  cfg.BatchMaxCount,
+			MaxBytes:  cfg.BatchMaxBytes,
+			MaxLinger: cfg.BatchMaxLinger,
+		}, func(logs []*model.Log, err error) {
+			batchFailed.Add(int64(len(logs)))
+			failure.record(err)
+
+			logger.Warn("SendLogs failed", append([]any{"transport", cfg.Transport, "count", len(logs), "error", err.Error()}, clientErrorArgs(err)...)...)
+		})
+		if err != nil {
+			logger.Error("invalid batch options", err)
+			cli.Close()
+
+			return 1
+		}
+
+		cli = batching
+	}
+
+	// 送信に失敗した行は警告を出して処理を継続する
+	onFailure := func(log *model.Log, err error) {
+		failure.record(err)
+
+		logger.Warn("SendLog failed", append([]any{"transport", cfg.Transport, "id", log.ID, "message", log.Message, "error", err.Error()}, clientErrorArgs(err)...)...)
+	}
+
+	stats, err := ingest.Ship(ctx, os.Stdin, cli, opts.template(cfg.TimestampLayouts), onFailure)
+
+	// バッファに残っているログを送信してからクローズする
+	cli.Close()
+
+	stats.Sent -= int(batchFailed.Load())
+	stats.Failed += int(batchFailed.Load())
+
+	logger.Info("stdin send finished", "transport", cfg.Transport, "sent", stats.Sent, "failed", stats.Failed)
+
+	if err != nil {
+		logger.Error("failed to read stdin", err)
+
+		return 1
+	}
+
+	if stats.Failed > 0 {
+		return failure.exitCode()
+	}
+
+	return 0
+}
@@ -0,0 +1,211 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 認証方式（config.Config.AuthType に指定する値）
+const (
+	AuthTypeBearer = "bearer"
+	AuthTypeAPIKey = "api-key"
+)
+
+// 認証に関するエラー
+var (
+	ErrUnknownAuthType      = errors.New("unknown auth type")
+	ErrMultipleTokenSources = errors.New("only one of AUTH_TOKEN, AUTH_TOKEN_FILE and AUTH_TOKEN_COMMAND can be specified")
+	ErrEmptyToken           = errors.New("auth token is empty")
+)
+
+// TokenSource は認証トークンを返すインターフェース
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken は固定の認証トークン
+type StaticToken string
+
+// Token は固定の認証トークンを返す
+func (t StaticToken) Token(context.Context) (string, error) {
+	if t == "" {
+		return "", ErrEmptyToken
+	}
+
+	return string(t), nil
+}
+
+// FileToken はファイルから読み込む認証トークン
+// ファイルの更新日時またはサイズが変わった場合に読み込み直す
+type FileToken struct {
+	path    string
+	mutex   sync.Mutex
+	modTime time.Time
+	size    int64
+	token   string
+}
+
+// NewFileToken は path のファイルから認証トークンを読み込む FileToken を生成する
+func NewFileToken(path string) *FileToken {
+	return &FileToken{path: path, mutex: sync.Mutex{}, modTime: time.Time{}, size: 0, token: ""}
+}
+
+// Token はファイルの内容（前後の空白を除く）を認証トークンとして返す
+func (t *FileToken) Token(context.Context) (string, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat token file: %w", err)
+	}
+
+	if t.token != "" && info.ModTime().Equal(t.modTime) && info.Size() == t.size {
+		return t.token, nil
+	}
+
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("%w: %s", ErrEmptyToken, t.path)
+	}
+
+	t.token, t.modTime, t.size = token, info.ModTime(), info.Size()
+
+	return t.token, nil
+}
+
+// CommandToken はコマンドの標準出力から取得する認証トークン
+// 取得したトークンは ttl の間使い回し、期限が切れたらコマンドを再実行する
+type CommandToken struct {
+	command string
+	ttl     time.Duration
+	mutex   sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewCommandToken は command を sh -c で実行して認証トークンを取得する CommandToken を生成する
+func NewCommandToken(command string, ttl time.Duration) *CommandToken {
+	return &CommandToken{command: command, ttl: ttl, mutex: sync.Mutex{}, token: "", expires: time.Time{}}
+}
+
+// Token はコマンドの標準出力（前後の空白を除く）を認証トークンとして返す
+func (t *CommandToken) Token(ctx context.Context) (string, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.token != "" && time.Now().Before(t.expires) {
+		return t.token, nil
+	}
+
+	output, err := exec.CommandContext(ctx, "sh", "-c", t.command).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run token command: %w", err)
+	}
+
+	token := strings.TrimSpace(string(output))
+	if token == "" {
+		return "", fmt.Errorf("%w: token command printed nothing", ErrEmptyToken)
+	}
+
+	t.token, t.expires = token, time.Now().Add(t.ttl)
+
+	return t.token, nil
+}
+
+// AuthOptions は認証方式とトークンの取得元を保持する構造体
+// Token / TokenFile / TokenCommand はいずれか一つのみ指定できる
+type AuthOptions struct {
+	Type         string        // 認証方式（bearer / api-key）
+	Token        string        // 認証トークン
+	TokenFile    string        // 認証トークンを読み込むファイル
+	TokenCommand string        // 認証トークンを出力するコマンド
+	TokenTTL     time.Duration // TokenCommand で取得したトークンを使い回す時間
+}
+
+// Authenticator はリクエストに付与する Authorization ヘッダーの値を生成する
+type Authenticator struct {
+	scheme string
+	source TokenSource
+}
+
+// NewAuthenticator は AuthOptions から Authenticator を生成する
+// トークンの取得元が一つも指定されていない場合は nil を返す
+func NewAuthenticator(opts AuthOptions) (*Authenticator, error) {
+	var scheme string
+
+	switch opts.Type {
+	case AuthTypeBearer:
+		scheme = "Bearer"
+	case AuthTypeAPIKey:
+		scheme = "ApiKey"
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAuthType, opts.Type)
+	}
+
+	sources := make([]TokenSource, 0, 1)
+
+	if opts.Token != "" {
+		sources = append(sources, StaticToken(opts.Token))
+	}
+
+	if opts.TokenFile != "" {
+		sources = append(sources, NewFileToken(opts.TokenFile))
+	}
+
+	if opts.TokenCommand != "" {
+		sources = append(sources, NewCommandToken(opts.TokenCommand, opts.TokenTTL))
+	}
+
+	switch len(sources) {
+	case 0:
+		return nil, nil //nolint:nilnil // 認証なしを表す
+	case 1:
+		return &Authenticator{scheme: scheme, source: sources[0]}, nil
+	default:
+		return nil, ErrMultipleTokenSources
+	}
+}
+
+// Header は Authorization ヘッダーの値（例: "Bearer xxx"）を返す
+func (a *Authenticator) Header(ctx context.Context) (string, error) {
+	token, err := a.source.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get auth token: %w", err)
+	}
+
+	return a.scheme + " " + token, nil
+}
+
+// perRPCCredentials は Authenticator を gRPC の PerRPCCredentials として扱うためのアダプター
+type perRPCCredentials struct {
+	auth       *Authenticator
+	requireTLS bool
+}
+
+// GetRequestMetadata は RPC ごとに authorization メタデータを返す
+func (c perRPCCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	header, err := c.auth.Header(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{"authorization": header}, nil
+}
+
+// RequireTransportSecurity は TLS 接続でのみトークンを送信するかを返す
+// GRPC_INSECURE が明示された場合のみ平文の接続でも送信する
+func (c perRPCCredentials) RequireTransportSecurity() bool {
+	return c.requireTLS
+}
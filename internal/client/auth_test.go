@@ -0,0 +1,171 @@
+package client_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/config"
+	pb "github.com/KeitaShimura/logs-collector-protos/go/logs/v1"
+)
+
+// authRecordingServer は受信した authorization メタデータを記録する gRPC サーバー
+type authRecordingServer struct {
+	pb.UnimplementedLogServiceServer
+
+	mutex   sync.Mutex
+	headers []string
+}
+
+// SendLog は authorization メタデータを記録する
+func (s *authRecordingServer) SendLog(ctx context.Context, _ *pb.SendLogRequest) (*pb.SendLogResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.headers = append(s.headers, md.Get("authorization")...)
+
+	return &pb.SendLogResponse{}, nil
+}
+
+// TestFileToken はトークンファイルの変更後に新しいトークンを読み込むことを検証する
+func TestFileToken(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("first\n"), 0o600))
+
+	source := client.NewFileToken(path)
+
+	token, err := source.Token(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "first", token)
+
+	require.NoError(t, os.WriteFile(path, []byte("rotated-token\n"), 0o600))
+
+	token, err = source.Token(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "rotated-token", token)
+
+	require.NoError(t, os.WriteFile(path, []byte("  \n"), 0o600))
+
+	_, err = source.Token(context.Background())
+	require.ErrorIs(t, err, client.ErrEmptyToken)
+}
+
+// TestCommandToken はコマンドの出力をトークンとし、TTL の間は再実行しないことを検証する
+func TestCommandToken(t *testing.T) {
+	t.Parallel()
+
+	counter := filepath.Join(t.TempDir(), "count")
+	source := client.NewCommandToken("echo run >> "+counter+"; echo command-token", time.Hour)
+
+	for range 3 {
+		token, err := source.Token(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "command-token", token)
+	}
+
+	runs, err := os.ReadFile(counter)
+	require.NoError(t, err)
+	require.Equal(t, "run\n", string(runs))
+}
+
+// TestNewAuthenticator_Invalid は不正な認証設定がエラーになることを検証する
+func TestNewAuthenticator_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := client.NewAuthenticator(client.AuthOptions{Type: "basic", Token: "x", TokenFile: "", TokenCommand: "", TokenTTL: 0})
+	require.ErrorIs(t, err, client.ErrUnknownAuthType)
+
+	_, err = client.NewAuthenticator(client.AuthOptions{Type: client.AuthTypeBearer, Token: "x", TokenFile: "token", TokenCommand: "", TokenTTL: 0})
+	require.ErrorIs(t, err, client.ErrMultipleTokenSources)
+
+	auth, err := client.NewAuthenticator(client.AuthOptions{Type: client.AuthTypeBearer, Token: "", TokenFile: "", TokenCommand: "", TokenTTL: 0})
+	require.NoError(t, err)
+	require.Nil(t, auth)
+}
+
+// TestRESTClient_Authorization は REST のリクエストに Authorization ヘッダーが付与されることを検証する
+func TestRESTClient_Authorization(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mutex   sync.Mutex
+		headers []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		headers = append(headers, r.Header.Get("Authorization"))
+		mutex.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("key-1"), 0o600))
+
+	auth, err := client.NewAuthenticator(client.AuthOptions{Type: client.AuthTypeAPIKey, Token: "", TokenFile: path, TokenCommand: "", TokenTTL: 0})
+	require.NoError(t, err)
+
+	cli := client.NewRESTClient(server.URL, client.WithAuthenticator(auth))
+	require.NoError(t, cli.SendLog(context.Background(), newLog("first")))
+
+	require.NoError(t, os.WriteFile(path, []byte("key-two"), 0o600))
+	require.NoError(t, cli.SendLog(context.Background(), newLog("second")))
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	require.Equal(t, []string{"ApiKey key-1", "ApiKey key-two"}, headers)
+}
+
+// TestGRPCClient_Authorization は gRPC のリクエストに authorization メタデータが付与されることを検証する
+func TestGRPCClient_Authorization(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	recorder := &authRecordingServer{UnimplementedLogServiceServer: pb.UnimplementedLogServiceServer{}, mutex: sync.Mutex{}, headers: nil}
+	server := grpc.NewServer()
+	pb.RegisterLogServiceServer(server, recorder)
+
+	go server.Serve(listener) //nolint:errcheck // Stop 時のエラーは検証対象外
+	defer server.Stop()
+
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	cfg.Transport = client.TransportGRPC
+	cfg.GRPCEndpoint = listener.Addr().String()
+	cfg.GRPCInsecure = true
+	cfg.AuthType = client.AuthTypeBearer
+	cfg.AuthToken = "secret"
+	cfg.RetryMaxAttempts = 1
+
+	cli, err := client.New(cfg)
+	require.NoError(t, err)
+	defer cli.Close()
+
+	require.NoError(t, cli.SendLog(context.Background(), newLog("hello")))
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	require.Equal(t, []string{"Bearer secret"}, recorder.headers)
+}
@@ -0,0 +1,227 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// BatchingClient のエラー
+var (
+	// ErrBatchingClientClosed は、Close 後に SendLog が呼ばれた場合のエラー
+	ErrBatchingClientClosed = errors.New("batching client is closed")
+	// ErrInvalidBatchOptions は、BatchOptions の値が不正な場合のエラー
+	ErrInvalidBatchOptions = errors.New("invalid batch options")
+)
+
+// BatchSender は複数のログをまとめて送信できるクライアントが実装するインターフェース
+type BatchSender interface {
+	SendLogs(ctx context.Context, logs []*model.Log) error
+}
+
+// BatchOptions はバッチ送信のフラッシュ条件を保持する構造体
+// いずれかの条件を満たした時点でバッファ中のログを送信する
+type BatchOptions struct {
+	MaxCount  int           // 1 バッチあたりの最大件数（1 以上）
+	MaxBytes  int           // 1 バッチあたりの最大バイト数（JSON 換算。0 以下は無制限）
+	MaxLinger time.Duration // 最初のログをバッファしてから送信するまでの最大待ち時間
+}
+
+// FlushErrorHandler はフラッシュに失敗したログとエラーを受け取るコールバック
+// 1 件ずつ送信した場合、logs には送信に失敗したログのみが渡される
+type FlushErrorHandler func(logs []*model.Log, err error)
+
+// PartialSendError は、ログを 1 件ずつ送信した際に一部のログの送信に失敗した場合のエラー
+// errors.As で取り出し、送信に失敗したログを参照できる（それ以外のログは送信済み）
+type PartialSendError struct {
+	Failed []*model.Log // 送信に失敗したログ
+	Err    error        // ログごとのエラーをまとめたエラー
+}
+
+// Error はエラーメッセージを返す
+func (e *PartialSendError) Error() string {
+	return fmt.Sprintf("failed to send %d logs: %v", len(e.Failed), e.Err)
+}
+
+// Unwrap は原因となったエラーを返す
+func (e *PartialSendError) Unwrap() error {
+	return e.Err
+}
+
+// BatchingClient は Client をラップし、ログをバッファしてまとめて送信するクライアント
+type BatchingClient struct {
+	inner   Client
+	opts    BatchOptions
+	onError FlushErrorHandler
+
+	mutex   sync.Mutex // buffer / size / timer / closed を保護する
+	buffer  []*model.Log
+	size    int
+	timer   *time.Timer
+	closed  bool
+	sending sync.Mutex // 送信順序を保つためフラッシュを直列化する
+}
+
+// NewBatchingClient は inner をラップする BatchingClient を生成する
+// 送信の失敗はすべて onError に通知される。Flush / Close は同じエラーを戻り値としても返す
+// MaxCount が 1 未満の場合は ErrInvalidBatchOptions を返す（config.Validate の BATCH_MAX_COUNT と同じ範囲）
+func NewBatchingClient(inner Client, opts BatchOptions, onError FlushErrorHandler) (*BatchingClient, error) {
+	if opts.MaxCount < 1 {
+		return nil, fmt.Errorf("%w: MaxCount must be at least 1, got %d", ErrInvalidBatchOptions, opts.MaxCount)
+	}
+
+	return &BatchingClient{
+		inner:   inner,
+		opts:    opts,
+		onError: onError,
+		mutex:   sync.Mutex{},
+		buffer:  nil,
+		size:    0,
+		timer:   nil,
+		closed:  false,
+		sending: sync.Mutex{},
+	}, nil
+}
+
+// SendLog はログをバッファに追加し、件数・サイズの上限に達した場合は送信する
+// 上限到達による送信の失敗は onError にのみ通知し、戻り値はバッファへの追加可否を表す
+// 不正なログはバッチ全体の送信を失敗させないよう、バッファに追加せずエラーを返す
+func (c *BatchingClient) SendLog(ctx context.Context, log *model.Log) error {
+	if err := prepareLog(log); err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(log)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log: %w", err)
+	}
+
+	c.mutex.Lock()
+
+	if c.closed {
+		c.mutex.Unlock()
+
+		return ErrBatchingClientClosed
+	}
+
+	c.buffer = append(c.buffer, log)
+	c.size += len(encoded)
+
+	full := len(c.buffer) >= c.opts.MaxCount || (c.opts.MaxBytes > 0 && c.size >= c.opts.MaxBytes)
+
+	// バッファの先頭が追加された時点でリンガータイマーを開始する
+	if !full && c.timer == nil && c.opts.MaxLinger > 0 {
+		c.timer = time.AfterFunc(c.opts.MaxLinger, c.flushOnLinger)
+	}
+
+	c.mutex.Unlock()
+
+	if full {
+		_ = c.Flush(ctx)
+	}
+
+	return nil
+}
+
+// GetLogs はラップしているクライアントでログを取得する
+func (c *BatchingClient) GetLogs(ctx context.Context, query *model.LogQuery) ([]*model.Log, error) {
+	return c.inner.GetLogs(ctx, query) //nolint:wrapcheck // ラップ元のエラーをそのまま返す
+}
+
+// Flush はバッファ中のログをすべて送信する
+func (c *BatchingClient) Flush(ctx context.Context) error {
+	c.sending.Lock()
+	defer c.sending.Unlock()
+
+	batch := c.take()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	return c.send(ctx, batch)
+}
+
+// Close はバッファ中のログを送信してから、ラップしているクライアントをクローズする
+func (c *BatchingClient) Close() error {
+	c.mutex.Lock()
+	c.closed = true
+	c.mutex.Unlock()
+
+	return errors.Join(c.Flush(context.Background()), c.inner.Close())
+}
+
+// take はバッファ中のログを取り出し、バッファとタイマーをリセットする
+func (c *BatchingClient) take() []*model.Log {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+
+	batch := c.buffer
+	c.buffer = nil
+	c.size = 0
+
+	return batch
+}
+
+// send はバッチを送信し、失敗した場合は送信できなかったログを onError に通知する
+func (c *BatchingClient) send(ctx context.Context, batch []*model.Log) error {
+	var err error
+
+	if batchSender, ok := c.inner.(BatchSender); ok {
+		err = batchSender.SendLogs(ctx, batch)
+	} else {
+		err = sendEach(ctx, c.inner, batch)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	failed := batch
+	if partial := (*PartialSendError)(nil); errors.As(err, &partial) {
+		failed = partial.Failed
+	}
+
+	if c.onError != nil {
+		c.onError(failed, err)
+	}
+
+	return fmt.Errorf("failed to send batch of %d logs: %w", len(batch), err)
+}
+
+// flushOnLinger はリンガー時間経過時にバックグラウンドでフラッシュする
+// エラーは send 内で onError に通知済みのため破棄する
+func (c *BatchingClient) flushOnLinger() {
+	_ = c.Flush(context.Background())
+}
+
+// sendEach はログを 1 件ずつ順に送信する
+// 失敗したログがあっても残りのログの送信を続け、失敗したログを PartialSendError として返す
+func sendEach(ctx context.Context, sender Client, logs []*model.Log) error {
+	var (
+		failed []*model.Log
+		errs   []error
+	)
+
+	for i, log := range logs {
+		if err := sender.SendLog(ctx, log); err != nil {
+			failed = append(failed, log)
+			errs = append(errs, fmt.Errorf("log %d/%d: %w", i+1, len(logs), err))
+		}
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	return &PartialSendError{Failed: failed, Err: errors.Join(errs...)}
+}
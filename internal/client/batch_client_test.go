@@ -0,0 +1,246 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// 共通エラー定義
+var errBatchFailed = errors.New("batch failed")
+
+// recordingClient は送信されたバッチを記録するテスト用の Client
+type recordingClient struct {
+	mutex   sync.Mutex
+	batches [][]*model.Log
+	err     error
+	closed  bool
+}
+
+// SendLog は 1 件のバッチとして記録する
+func (c *recordingClient) SendLog(ctx context.Context, log *model.Log) error {
+	return c.SendLogs(ctx, []*model.Log{log})
+}
+
+// SendLogs はバッチを記録し、err が設定されていればそれを返す
+func (c *recordingClient) SendLogs(_ context.Context, logs []*model.Log) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	c.batches = append(c.batches, logs)
+
+	return nil
+}
+
+// GetLogs は常に空の結果を返す
+func (c *recordingClient) GetLogs(context.Context, *model.LogQuery) ([]*model.Log, error) {
+	return nil, nil
+}
+
+// Close はクローズされたことを記録する
+func (c *recordingClient) Close() error {
+	c.closed = true
+
+	return nil
+}
+
+// singleClient は 1 件ずつ送信するテスト用の Client（BatchSender を実装しない）
+// メッセージが failing と一致するログは errBatchFailed で失敗し、それ以外は記録する
+type singleClient struct {
+	mutex   sync.Mutex
+	failing string
+	sent    []string
+}
+
+// SendLog は failing のログを失敗させ、それ以外はメッセージを記録する
+func (c *singleClient) SendLog(_ context.Context, log *model.Log) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if log.Message == c.failing {
+		return errBatchFailed
+	}
+
+	c.sent = append(c.sent, log.Message)
+
+	return nil
+}
+
+// GetLogs は常に空の結果を返す
+func (c *singleClient) GetLogs(context.Context, *model.LogQuery) ([]*model.Log, error) {
+	return nil, nil
+}
+
+// Close は何もしない
+func (c *singleClient) Close() error {
+	return nil
+}
+
+// sizes は記録されたバッチごとの件数を返す
+func (c *recordingClient) sizes() []int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	sizes := make([]int, 0, len(c.batches))
+	for _, batch := range c.batches {
+		sizes = append(sizes, len(batch))
+	}
+
+	return sizes
+}
+
+// newLog はテスト用のログを生成する
+func newLog(message string) *model.Log {
+	return &model.Log{
+		ID:        message,
+		TraceID:   "",
+		Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Level:     "INFO",
+		Service:   "test-service",
+		Message:   message,
+		Metadata:  nil,
+	}
+}
+
+// TestBatchingClient_FlushOnMaxCount は件数上限に達した時点で送信されることを検証する
+func TestBatchingClient_FlushOnMaxCount(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingClient{mutex: sync.Mutex{}, batches: nil, err: nil, closed: false}
+	batcher, err := client.NewBatchingClient(inner, client.BatchOptions{MaxCount: 2, MaxBytes: 0, MaxLinger: 0}, nil)
+	require.NoError(t, err)
+
+	for _, message := range []string{"a", "b", "c"} {
+		require.NoError(t, batcher.SendLog(context.Background(), newLog(message)))
+	}
+
+	require.Equal(t, []int{2}, inner.sizes())
+
+	require.NoError(t, batcher.Close())
+	require.Equal(t, []int{2, 1}, inner.sizes())
+	require.True(t, inner.closed)
+}
+
+// TestBatchingClient_FlushOnMaxBytes はバイト数上限に達した時点で送信されることを検証する
+func TestBatchingClient_FlushOnMaxBytes(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingClient{mutex: sync.Mutex{}, batches: nil, err: nil, closed: false}
+	batcher, err := client.NewBatchingClient(inner, client.BatchOptions{MaxCount: 100, MaxBytes: 1, MaxLinger: 0}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, batcher.SendLog(context.Background(), newLog("a")))
+	require.Equal(t, []int{1}, inner.sizes())
+}
+
+// TestNewBatchingClient_InvalidOptions は MaxCount が 1 未満の場合にエラーを返すことを検証する
+func TestNewBatchingClient_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingClient{mutex: sync.Mutex{}, batches: nil, err: nil, closed: false}
+
+	for _, maxCount := range []int{0, -1} {
+		_, err := client.NewBatchingClient(inner, client.BatchOptions{MaxCount: maxCount, MaxBytes: 0, MaxLinger: 0}, nil)
+		require.ErrorIs(t, err, client.ErrInvalidBatchOptions)
+	}
+}
+
+// TestBatchingClient_FlushOnLinger はリンガー時間経過後にバックグラウンドで送信されることを検証する
+func TestBatchingClient_FlushOnLinger(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingClient{mutex: sync.Mutex{}, batches: nil, err: nil, closed: false}
+	batcher, err := client.NewBatchingClient(inner, client.BatchOptions{
+		MaxCount:  100,
+		MaxBytes:  0,
+		MaxLinger: 10 * time.Millisecond,
+	}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, batcher.SendLog(context.Background(), newLog("a")))
+	require.NoError(t, batcher.SendLog(context.Background(), newLog("b")))
+
+	require.Eventually(t, func() bool {
+		return len(inner.sizes()) == 1
+	}, time.Second, 5*time.Millisecond)
+	require.Equal(t, []int{2}, inner.sizes())
+}
+
+// TestBatchingClient_FlushError は送信失敗が onError と Flush の戻り値の両方で通知されることを検証する
+func TestBatchingClient_FlushError(t *testing.T) {
+	t.Parallel()
+
+	inner := &recordingClient{mutex: sync.Mutex{}, batches: nil, err: errBatchFailed, closed: false}
+
+	var failed []*model.Log
+
+	batcher, err := client.NewBatchingClient(inner, client.BatchOptions{MaxCount: 10, MaxBytes: 0, MaxLinger: 0},
+		func(logs []*model.Log, _ error) {
+			failed = append(failed, logs...)
+		})
+	require.NoError(t, err)
+
+	require.NoError(t, batcher.SendLog(context.Background(), newLog("a")))
+	require.ErrorIs(t, batcher.Flush(context.Background()), errBatchFailed)
+	require.Len(t, failed, 1)
+
+	require.NoError(t, batcher.Close())
+	require.ErrorIs(t, batcher.SendLog(context.Background(), newLog("b")), client.ErrBatchingClientClosed)
+}
+
+// TestBatchingClient_PartialFailure は 1 件ずつ送信する場合に、失敗したログの後も送信を続け、
+// onError には失敗したログのみが渡されることを検証する（RetryingClient でラップした場合も同様）
+func TestBatchingClient_PartialFailure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		wrap func(client.Client) client.Client
+	}{
+		{name: "そのまま", wrap: func(inner client.Client) client.Client { return inner }},
+		{name: "RetryingClient", wrap: func(inner client.Client) client.Client { return client.NewRetryingClient(inner, testRetryPolicy) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			inner := &singleClient{mutex: sync.Mutex{}, failing: "b", sent: nil}
+
+			var failed []string
+
+			batcher, err := client.NewBatchingClient(tt.wrap(inner), client.BatchOptions{MaxCount: 10, MaxBytes: 0, MaxLinger: 0},
+				func(logs []*model.Log, _ error) {
+					for _, log := range logs {
+						failed = append(failed, log.Message)
+					}
+				})
+			require.NoError(t, err)
+
+			for _, message := range []string{"a", "b", "c"} {
+				require.NoError(t, batcher.SendLog(context.Background(), newLog(message)))
+			}
+
+			err = batcher.Flush(context.Background())
+			require.ErrorIs(t, err, errBatchFailed)
+
+			partial := (*client.PartialSendError)(nil)
+			require.ErrorAs(t, err, &partial)
+			require.Len(t, partial.Failed, 1)
+
+			require.Equal(t, []string{"a", "c"}, inner.sent)
+			require.Equal(t, []string{"b"}, failed)
+		})
+	}
+}
@@ -2,12 +2,134 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/config"
 	"github.com/KeitaShimura/logs-collector-client/internal/model"
 )
 
+// 通信方式（config.Config.Transport に指定する値）
+const (
+	TransportGRPC = "grpc"
+	TransportREST = "rest"
+)
+
+// ErrUnknownTransport は、未対応の通信方式が指定された場合のエラー
+var ErrUnknownTransport = errors.New("unknown transport")
+
 // Client はログの送信および取得を行うためのインターフェース
 type Client interface {
 	SendLog(ctx context.Context, log *model.Log) error
-	GetLogs(ctx context.Context, service string, level string, limit int, offset int) ([]model.Log, error)
+	GetLogs(ctx context.Context, query *model.LogQuery) ([]*model.Log, error)
+	Close() error
+}
+
+// インターフェースを満たしていることをコンパイル時に検証する
+var (
+	_ Client = (*GRPCClient)(nil)
+	_ Client = (*RESTClient)(nil)
+	_ Client = (*BatchingClient)(nil)
+	_ Client = (*RetryingClient)(nil)
+
+	_ BatchSender = (*RESTClient)(nil)
+	_ BatchSender = (*RetryingClient)(nil)
+)
+
+// prepareLog は送信前にログを正規化・検証する
+// gRPC / REST のどちらで送信する場合も同じ処理を適用する
+func prepareLog(log *model.Log) error {
+	log.Normalize(time.Now())
+
+	return log.Validate() //nolint:wrapcheck // 検証エラーをそのまま返す
+}
+
+// New は設定の Transport に応じて gRPC または REST のクライアントを生成する
+// 認証トークンが設定されている場合は、REST では Authorization ヘッダー、gRPC では authorization メタデータとして送信する
+// RetryMaxAttempts が 2 以上の場合は RetryingClient でラップする
+//
+//nolint:ireturn // 通信方式を呼び出し側から隠蔽するためインターフェースを返す
+func New(cfg *config.Config) (Client, error) {
+	var cli Client
+
+	tlsOptions := TLSOptions{
+		CAFile:     cfg.TLSCAFile,
+		CertFile:   cfg.TLSCertFile,
+		KeyFile:    cfg.TLSKeyFile,
+		ServerName: cfg.TLSServerName,
+	}
+
+	tlsConfig, err := NewTLSConfig(tlsOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := NewAuthenticator(AuthOptions{
+		Type:         cfg.AuthType,
+		Token:        cfg.AuthToken,
+		TokenFile:    cfg.AuthTokenFile,
+		TokenCommand: cfg.AuthTokenCommand,
+		TokenTTL:     cfg.AuthTokenTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Transport {
+	case TransportGRPC:
+		creds, err := grpcCredentials(cfg.GRPCInsecure, tlsOptions, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+		if auth != nil {
+			dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(perRPCCredentials{auth: auth, requireTLS: !cfg.GRPCInsecure}))
+		}
+
+		grpcClient, err := NewGRPCClient(cfg.GRPCEndpoint, dialOptions...)
+		if err != nil {
+			return nil, err
+		}
+
+		cli = grpcClient
+	case TransportREST:
+		cli = NewRESTClient(cfg.RESTEndpoint, WithHTTPClient(newHTTPClient(tlsConfig)), WithAuthenticator(auth))
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTransport, cfg.Transport)
+	}
+
+	if cfg.RetryMaxAttempts > 1 {
+		cli = NewRetryingClient(cli, RetryPolicy{
+			MaxAttempts:    cfg.RetryMaxAttempts,
+			InitialBackoff: cfg.RetryInitialBackoff,
+			MaxBackoff:     cfg.RetryMaxBackoff,
+			Multiplier:     cfg.RetryMultiplier,
+			Jitter:         cfg.RetryJitter,
+		})
+	}
+
+	return cli, nil
+}
+
+// grpcCredentials は gRPC 接続に使用する認証情報を返す
+// 平文での接続は insecure に true が明示された場合のみ許可し、TLS の設定との併用はエラーとする
+//
+//nolint:ireturn // gRPC の認証情報はインターフェースとして扱う
+func grpcCredentials(insecureConn bool, opts TLSOptions, tlsConfig *tls.Config) (credentials.TransportCredentials, error) {
+	if !insecureConn {
+		return credentials.NewTLS(tlsConfig), nil
+	}
+
+	if !opts.IsZero() {
+		return nil, ErrInsecureWithTLS
+	}
+
+	return insecure.NewCredentials(), nil
 }
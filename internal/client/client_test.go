@@ -0,0 +1,96 @@
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// TestRESTClient_SendLogNormalizes は REST でも送信前にログが正規化（タイムスタンプはナノ秒精度を保持）・検証されることを検証する
+func TestRESTClient_SendLogNormalizes(t *testing.T) {
+	t.Parallel()
+
+	var (
+		requests atomic.Int32
+		received model.Log
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		var body struct {
+			Log model.Log `json:"log"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		received = body.Log
+	}))
+	t.Cleanup(server.Close)
+
+	cli := client.NewRESTClient(server.URL)
+	log := &model.Log{
+		ID:        "",
+		TraceID:   "",
+		Timestamp: time.Date(2025, 1, 2, 12, 4, 5, 123456789, time.FixedZone("JST", 9*60*60)),
+		Level:     "warning",
+		Service:   "billing",
+		Message:   "paid",
+		Metadata:  nil,
+	}
+
+	require.NoError(t, cli.SendLog(context.Background(), log))
+	require.Equal(t, model.LevelWarn, received.Level)
+	require.NotEmpty(t, received.ID)
+	require.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC), received.Timestamp)
+
+	// 不正なログはサーバーに送信しない
+	invalid := &model.Log{ID: "", TraceID: "", Timestamp: time.Time{}, Level: "VERBOSE", Service: "", Message: "paid", Metadata: nil}
+
+	err := cli.SendLog(context.Background(), invalid)
+	require.ErrorIs(t, err, model.ErrInvalidLog)
+	require.ErrorIs(t, err, model.ErrMissingService)
+	require.ErrorIs(t, err, model.ErrUnknownLevel)
+	require.Equal(t, int32(1), requests.Load())
+
+	// BatchingClient はバッファに追加する前に検証する
+	batching, err := client.NewBatchingClient(cli, client.BatchOptions{MaxCount: 10, MaxBytes: 0, MaxLinger: 0}, nil)
+	require.NoError(t, err)
+	require.ErrorIs(t, batching.SendLog(context.Background(), invalid), model.ErrInvalidLog)
+	require.NoError(t, batching.Close())
+	require.Equal(t, int32(1), requests.Load())
+}
+
+// TestRESTClient_GetLogsInvalidTimestamp は解釈できない timestamp のログがあっても、結果全体をエラーにしないことを検証する
+func TestRESTClient_GetLogsInvalidTimestamp(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id":"1","timestamp":"2025-01-02T03:04:05Z","level":"INFO","service":"billing","message":"ok"},
+			{"id":"2","timestamp":"yesterday","level":"ERROR","service":"billing","message":"broken"}
+		]`))
+	}))
+	t.Cleanup(server.Close)
+
+	logs, err := client.NewRESTClient(server.URL).GetLogs(context.Background(), &model.LogQuery{Service: "", Level: "", StartTime: time.Time{}, EndTime: time.Time{}, Limit: 10, Offset: 0})
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	require.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), logs[0].Timestamp)
+	require.True(t, logs[1].Timestamp.IsZero())
+	require.Equal(t, "broken", logs[1].Message)
+	require.Equal(t, map[string]string{model.InvalidTimestampKey: "yesterday"}, logs[1].Metadata)
+}
@@ -0,0 +1,300 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// 失敗した操作（Error.Operation の値）
+const (
+	OperationSendLog  = "SendLog"
+	OperationSendLogs = "SendLogs"
+	OperationGetLogs  = "GetLogs"
+)
+
+// maxErrorBodyBytes はエラーレスポンスのボディから読み取る最大バイト数
+const maxErrorBodyBytes = 64 * 1024
+
+// 通信の失敗の種類を表すエラー（Error.Err から errors.Is で判定できる）
+var (
+	// ErrUnexpectedHTTPStatus は、想定外の HTTP ステータスが返された場合のエラー
+	ErrUnexpectedHTTPStatus = errors.New("unexpected HTTP status")
+	// ErrInvalidClientConfig は、証明書の検証の失敗や不正なホスト名など、再試行しても回復しない設定の誤りでリクエストを送信できない場合のエラー
+	ErrInvalidClientConfig = errors.New("invalid client configuration")
+)
+
+// Error はログ収集サーバーとの通信に失敗した場合のエラー
+// errors.As で取り出し、失敗した操作・サーバーが返した内容・再試行の可否を参照できる
+// HTTP ステータスによる失敗は errors.Is(err, ErrUnexpectedHTTPStatus) でも判定できる
+type Error struct {
+	Transport  string        // TransportGRPC / TransportREST
+	Operation  string        // OperationSendLog / OperationSendLogs / OperationGetLogs
+	StatusCode int           // HTTP ステータスコード（REST でレスポンスを受け取った場合のみ、それ以外は 0）
+	Code       codes.Code    // gRPC のステータスコード（REST では HTTP ステータスや失敗の種類から対応するコードを設定する）
+	Message    string        // サーバーが返したエラーメッセージ
+	Details    []string      // サーバーが返したエラーの詳細（JSON 文字列）
+	Retryable  bool          // 再試行で回復し得るか
+	RetryAfter time.Duration // サーバーが指定した再試行までの待機時間（指定なしは 0）
+	Err        error         // 原因となったエラー
+}
+
+// Error はエラーメッセージを返す
+func (e *Error) Error() string {
+	message := fmt.Sprintf("%s %s: %v", e.Transport, e.Operation, e.Err)
+
+	if e.StatusCode != 0 {
+		message += fmt.Sprintf(": %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+
+		if e.Message != "" {
+			message += ": " + e.Message
+		}
+	}
+
+	return message
+}
+
+// Unwrap は原因となったエラーを返す
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// newHTTPError はエラーを示す HTTP レスポンスから Error を生成する
+// レスポンスボディからメッセージと詳細を読み取る
+func newHTTPError(operation string, res *http.Response) *Error {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodyBytes))
+	message, details := decodeErrorBody(body)
+
+	return &Error{
+		Transport:  TransportREST,
+		Operation:  operation,
+		StatusCode: res.StatusCode,
+		Code:       codeFromHTTPStatus(res.StatusCode),
+		Message:    message,
+		Details:    details,
+		Retryable:  res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError,
+		RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), time.Now()),
+		Err:        ErrUnexpectedHTTPStatus,
+	}
+}
+
+// newRequestError は REST のリクエストがレスポンスを受け取る前に失敗した場合の Error を生成する
+// 証明書の検証の失敗など設定の誤りは FailedPrecondition（ErrInvalidClientConfig）、キャンセルは Canceled として再試行しない
+// それ以外は接続の問題・期限切れとみなし、gRPC の Unavailable / DeadlineExceeded と同様に再試行可能とする
+func newRequestError(operation string, err error) *Error {
+	code := codes.Unavailable
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case isConfigError(err):
+		code = codes.FailedPrecondition
+		err = fmt.Errorf("%w: %w", ErrInvalidClientConfig, err)
+	}
+
+	return &Error{
+		Transport:  TransportREST,
+		Operation:  operation,
+		StatusCode: 0,
+		Code:       code,
+		Message:    "",
+		Details:    nil,
+		Retryable:  isRetryableCode(code),
+		RetryAfter: 0,
+		Err:        err,
+	}
+}
+
+// newGRPCError は gRPC の呼び出しのエラーから Error を生成する
+// status の details は JSON に変換し、RetryInfo があれば再試行までの待機時間として使用する
+func newGRPCError(operation string, err error) *Error {
+	grpcStatus := status.Convert(err)
+
+	var (
+		details    []string
+		retryAfter time.Duration
+	)
+
+	for _, detail := range grpcStatus.Proto().GetDetails() {
+		encoded, err := protojson.Marshal(detail)
+		if err != nil {
+			// 型が登録されていない詳細は型名のみを残す
+			encoded = []byte(fmt.Sprintf(`{"@type":%q}`, detail.GetTypeUrl()))
+		}
+
+		details = append(details, string(encoded))
+	}
+
+	for _, detail := range grpcStatus.Details() {
+		if retryInfo, ok := detail.(*errdetails.RetryInfo); ok {
+			retryAfter = retryInfo.GetRetryDelay().AsDuration()
+		}
+	}
+
+	return &Error{
+		Transport:  TransportGRPC,
+		Operation:  operation,
+		StatusCode: 0,
+		Code:       grpcStatus.Code(),
+		Message:    grpcStatus.Message(),
+		Details:    details,
+		Retryable:  isRetryableCode(grpcStatus.Code()),
+		RetryAfter: retryAfter,
+		Err:        err,
+	}
+}
+
+// isConfigError は、証明書の検証の失敗や不正なホスト名など、再試行しても回復しない設定の誤りによるエラーかを返す
+func isConfigError(err error) bool {
+	var (
+		verificationErr *tls.CertificateVerificationError
+		authorityErr    x509.UnknownAuthorityError
+		hostnameErr     x509.HostnameError
+		certificateErr  x509.CertificateInvalidError
+		rootsErr        x509.SystemRootsError
+		hostErr         url.InvalidHostError
+	)
+
+	return errors.As(err, &verificationErr) || errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) ||
+		errors.As(err, &certificateErr) || errors.As(err, &rootsErr) || errors.As(err, &hostErr)
+}
+
+// isRetryableCode はステータスコードが再試行で回復し得るかを返す（REST・gRPC 共通）
+// 期限切れ（DeadlineExceeded）も再試行可能とし、呼び出し元のコンテキストが終了している場合は RetryingClient が再試行しない
+func isRetryableCode(code codes.Code) bool {
+	switch code { //nolint:exhaustive // 再試行対象のコード以外はすべて再試行しない
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// codeFromHTTPStatus は HTTP ステータスに対応する gRPC のステータスコードを返す
+func codeFromHTTPStatus(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+
+	switch {
+	case statusCode >= http.StatusInternalServerError:
+		return codes.Internal
+	case statusCode >= http.StatusBadRequest:
+		return codes.FailedPrecondition
+	default:
+		return codes.Unknown
+	}
+}
+
+// errorBody はエラーレスポンスの JSON ボディ
+// {"error": "..."}、{"message": "...", "details": [...]}、{"error": {"message": "...", "details": [...]}} の形式に対応する
+type errorBody struct {
+	Error   json.RawMessage   `json:"error"`
+	Message string            `json:"message"`
+	Details []json.RawMessage `json:"details"`
+}
+
+// decodeErrorBody はエラーレスポンスのボディからメッセージと詳細を取り出す
+// JSON として解釈できない場合はボディ全体をメッセージとする
+func decodeErrorBody(body []byte) (string, []string) {
+	body = bytes.TrimSpace(body)
+
+	var decoded errorBody
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return strings.ToValidUTF8(string(body), "�"), nil
+	}
+
+	message := decoded.Message
+	rawDetails := decoded.Details
+
+	var errorMessage string
+
+	switch {
+	case json.Unmarshal(decoded.Error, &errorMessage) == nil:
+		if message == "" {
+			message = errorMessage
+		}
+	case len(decoded.Error) > 0 && decoded.Error[0] == '{':
+		nestedMessage, nestedDetails := decodeErrorBody(decoded.Error)
+		if message == "" {
+			message = nestedMessage
+		}
+
+		if len(rawDetails) == 0 {
+			return message, nestedDetails
+		}
+	}
+
+	details := make([]string, 0, len(rawDetails))
+
+	for _, raw := range rawDetails {
+		var text string
+		if json.Unmarshal(raw, &text) == nil {
+			details = append(details, text)
+
+			continue
+		}
+
+		var compacted bytes.Buffer
+		if json.Compact(&compacted, raw) == nil {
+			details = append(details, compacted.String())
+		}
+	}
+
+	if len(details) == 0 {
+		details = nil
+	}
+
+	return message, details
+}
+
+// parseRetryAfter は Retry-After ヘッダー（秒数または HTTP 日付）を待機時間に変換する
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
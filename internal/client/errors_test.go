@@ -0,0 +1,181 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/collectortest"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// TestError_FromServer は gRPC / REST のどちらでもサーバーが返したエラーを Error として取り出せることを検証する
+func TestError_FromServer(t *testing.T) {
+	t.Parallel()
+
+	server := collectortest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.InjectFault(collectortest.Fault{
+		Method:  collectortest.MethodSendLog,
+		Times:   0,
+		Latency: 0,
+		Code:    codes.InvalidArgument,
+		Details: []proto.Message{&errdetails.ErrorInfo{Reason: "SERVICE_BLOCKED", Domain: "logs.example.com", Metadata: nil}},
+		Drop:    false,
+	})
+
+	for transport, cli := range newTransportClients(t, server) {
+		err := cli.SendLog(context.Background(), newLog("rejected"))
+
+		clientErr := (*client.Error)(nil)
+		require.ErrorAs(t, err, &clientErr, transport)
+		require.Equal(t, transport, clientErr.Transport)
+		require.Equal(t, client.OperationSendLog, clientErr.Operation, transport)
+		require.Equal(t, codes.InvalidArgument, clientErr.Code, transport)
+		require.Equal(t, "injected fault: InvalidArgument", clientErr.Message, transport)
+		require.False(t, clientErr.Retryable, transport)
+		require.Len(t, clientErr.Details, 1, transport)
+		require.Contains(t, clientErr.Details[0], "SERVICE_BLOCKED", transport)
+		require.Contains(t, clientErr.Details[0], "google.rpc.ErrorInfo", transport)
+
+		if transport == client.TransportREST {
+			require.Equal(t, http.StatusBadRequest, clientErr.StatusCode)
+			require.ErrorIs(t, err, client.ErrUnexpectedHTTPStatus)
+		} else {
+			require.Zero(t, clientErr.StatusCode)
+		}
+	}
+}
+
+// TestError_GRPCRetryInfo は gRPC の RetryInfo を再試行までの待機時間として使用することを検証する
+func TestError_GRPCRetryInfo(t *testing.T) {
+	t.Parallel()
+
+	server := collectortest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.InjectFault(collectortest.Fault{
+		Method:  collectortest.MethodGetLogs,
+		Times:   1,
+		Latency: 0,
+		Code:    codes.Unavailable,
+		Details: []proto.Message{&errdetails.RetryInfo{RetryDelay: durationpb.New(2 * time.Second)}},
+		Drop:    false,
+	})
+
+	cli := newTransportClients(t, server)[client.TransportGRPC]
+
+	_, err := cli.GetLogs(context.Background(), &model.LogQuery{Service: "", Level: "", StartTime: time.Time{}, EndTime: time.Time{}, Limit: 10, Offset: 0})
+
+	clientErr := (*client.Error)(nil)
+	require.ErrorAs(t, err, &clientErr)
+	require.Equal(t, client.OperationGetLogs, clientErr.Operation)
+	require.True(t, clientErr.Retryable)
+	require.Equal(t, 2*time.Second, clientErr.RetryAfter)
+
+	retryable, retryAfter := client.IsRetryable(err)
+	require.True(t, retryable)
+	require.Equal(t, 2*time.Second, retryAfter)
+}
+
+// TestError_RESTBody は REST のエラーレスポンスのボディからメッセージと詳細を読み取ることを検証する
+func TestError_RESTBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		code    codes.Code
+		message string
+		details []string
+	}{
+		{"plain text", http.StatusInternalServerError, "database is down\n", codes.Internal, "database is down", nil},
+		{"error string", http.StatusUnauthorized, `{"error": "token expired"}`, codes.Unauthenticated, "token expired", nil},
+		{
+			"message and details", http.StatusUnprocessableEntity,
+			`{"message": "validation failed", "details": ["service is required", {"field": "level"}]}`,
+			codes.FailedPrecondition, "validation failed", []string{"service is required", `{"field":"level"}`},
+		},
+		{
+			"nested error object", http.StatusTooManyRequests,
+			`{"error": {"code": 429, "message": "quota exceeded", "details": [{"reason": "RATE_LIMIT"}]}}`,
+			codes.ResourceExhausted, "quota exceeded", []string{`{"reason":"RATE_LIMIT"}`},
+		},
+		{"empty body", http.StatusForbidden, "", codes.PermissionDenied, "", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+
+			err := client.NewRESTClient(server.URL).SendLogs(context.Background(), []*model.Log{newLog("a")})
+
+			clientErr := (*client.Error)(nil)
+			require.ErrorAs(t, err, &clientErr)
+			require.Equal(t, client.OperationSendLogs, clientErr.Operation)
+			require.Equal(t, test.status, clientErr.StatusCode)
+			require.Equal(t, test.code, clientErr.Code)
+			require.Equal(t, test.message, clientErr.Message)
+			require.Equal(t, test.details, clientErr.Details)
+		})
+	}
+}
+
+// TestError_RESTConnectionFailure は接続できない場合に再試行可能な Error を返すことを検証する
+func TestError_RESTConnectionFailure(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	err := client.NewRESTClient(server.URL).SendLog(context.Background(), newLog("a"))
+
+	clientErr := (*client.Error)(nil)
+	require.ErrorAs(t, err, &clientErr)
+	require.Equal(t, codes.Unavailable, clientErr.Code)
+	require.Zero(t, clientErr.StatusCode)
+	require.True(t, clientErr.Retryable)
+	require.NotErrorIs(t, err, client.ErrUnexpectedHTTPStatus)
+}
+
+// TestError_RESTCertificateFailure は証明書の検証に失敗した場合に、再試行しない設定の誤りとして Error を返すことを検証する
+func TestError_RESTCertificateFailure(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+	}))
+	t.Cleanup(server.Close)
+
+	// 自己署名証明書を信頼していないクライアントで接続する
+	retrying := client.NewRetryingClient(client.NewRESTClient(server.URL), testRetryPolicy)
+	err := retrying.SendLog(context.Background(), newLog("a"))
+
+	clientErr := (*client.Error)(nil)
+	require.ErrorAs(t, err, &clientErr)
+	require.Equal(t, codes.FailedPrecondition, clientErr.Code)
+	require.False(t, clientErr.Retryable)
+	require.ErrorIs(t, err, client.ErrInvalidClientConfig)
+	require.NotContains(t, err.Error(), "giving up")
+	require.Zero(t, requests.Load())
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// Follow は query の条件で interval ごとに GetLogs を繰り返し、新しいログを handle に渡す（tail -f 相当）
+//   - 2 回目以降は取得済みのログの最新の timestamp を StartTime として取得する
+//   - StartTime と同じ timestamp のログは前回と重複し得るため、ID で重複を除く
+//   - 取得に失敗した場合は onError に渡して次の周期で再試行する
+//
+// ctx がキャンセルされるまで続け、handle がエラーを返した場合はそのエラーで終了する
+func Follow(
+	ctx context.Context,
+	cli Client,
+	query model.LogQuery,
+	interval time.Duration,
+	handle func(logs []*model.Log) error,
+	onError func(err error),
+) error {
+	follower := &follower{cli: cli, query: query, cursor: query.StartTime, seen: make(map[string]time.Time)}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for first := true; ; {
+		logs, err := follower.poll(ctx, first)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			onError(err)
+		default:
+			first = false
+
+			if len(logs) > 0 {
+				if err := handle(logs); err != nil {
+					return err
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// follower は Follow の取得位置と取得済みのログを保持する構造体
+type follower struct {
+	cli    Client
+	query  model.LogQuery
+	cursor time.Time            // 次回取得する範囲の開始時刻
+	seen   map[string]time.Time // cursor 以降の取得済みログの ID と timestamp
+}
+
+// poll は cursor 以降のログを取得し、未取得のログを timestamp の昇順で返す
+// 初回は指定された条件の 1 ページのみ、2 回目以降は Limit ごとにページを進めて全件を取得する
+func (f *follower) poll(ctx context.Context, first bool) ([]*model.Log, error) {
+	query := f.query
+
+	if !first {
+		query.StartTime = f.cursor
+		query.Offset = 0
+	}
+
+	started := time.Now()
+	fetched := make([]*model.Log, 0)
+
+	for {
+		page, err := f.cli.GetLogs(ctx, &query)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // クライアントのエラーをそのまま返す
+		}
+
+		fetched = append(fetched, page...)
+
+		if first || query.Limit <= 0 || len(page) < int(query.Limit) {
+			break
+		}
+
+		query.Offset += int32(len(page)) //nolint:gosec // 件数は Limit（int32）以下
+	}
+
+	return f.accept(fetched, started), nil
+}
+
+// accept は取得したログから未取得のものを選び、cursor を最新の timestamp まで進める
+// ログが一件もない場合は取得を開始した時刻を cursor とする
+func (f *follower) accept(logs []*model.Log, started time.Time) []*model.Log {
+	fresh := make([]*model.Log, 0, len(logs))
+	latest := f.cursor
+	timestamps := make(map[*model.Log]time.Time, len(logs))
+
+	for _, log := range logs {
+		timestamp := log.Timestamp
+		if timestamp.IsZero() {
+			timestamp = f.cursor
+		}
+
+		if log.ID != "" {
+			if _, ok := f.seen[log.ID]; ok {
+				continue
+			}
+
+			f.seen[log.ID] = timestamp
+		}
+
+		timestamps[log] = timestamp
+		fresh = append(fresh, log)
+
+		if timestamp.After(latest) {
+			latest = timestamp
+		}
+	}
+
+	if latest.IsZero() {
+		latest = started
+	}
+
+	f.cursor = latest
+
+	// cursor より前のログは次回以降の取得範囲に含まれないため忘れる
+	for id, timestamp := range f.seen {
+		if timestamp.Before(f.cursor) {
+			delete(f.seen, id)
+		}
+	}
+
+	slices.SortStableFunc(fresh, func(a, b *model.Log) int {
+		return timestamps[a].Compare(timestamps[b])
+	})
+
+	return fresh
+}
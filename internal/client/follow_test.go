@@ -0,0 +1,103 @@
+package client_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// storeClient はメモリ上のログを StartTime / Limit / Offset で絞り込んで返すクライアント
+type storeClient struct {
+	mutex sync.Mutex
+	logs  []*model.Log
+}
+
+// add は timestamp を指定してログを追加する
+func (c *storeClient) add(id string, timestamp time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.logs = append(c.logs, &model.Log{
+		ID:        id,
+		TraceID:   "",
+		Timestamp: timestamp,
+		Level:     "INFO",
+		Service:   "test-service",
+		Message:   id,
+		Metadata:  nil,
+	})
+}
+
+// SendLog は何もしない
+func (c *storeClient) SendLog(context.Context, *model.Log) error {
+	return nil
+}
+
+// GetLogs は StartTime 以降（同時刻を含む）のログを Offset から Limit 件返す
+func (c *storeClient) GetLogs(_ context.Context, query *model.LogQuery) ([]*model.Log, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	matched := make([]*model.Log, 0)
+
+	for _, log := range c.logs {
+		if !log.Timestamp.Before(query.StartTime) {
+			matched = append(matched, log)
+		}
+	}
+
+	start := min(int(query.Offset), len(matched))
+	end := min(start+int(query.Limit), len(matched))
+
+	return matched[start:end], nil
+}
+
+// Close は何もしない
+func (c *storeClient) Close() error {
+	return nil
+}
+
+// TestFollow は重複なく新しいログのみを順に受け取れることを検証する
+func TestFollow(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &storeClient{mutex: sync.Mutex{}, logs: nil}
+	store.add("a", base)
+	store.add("b", base.Add(time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var received []string
+
+	handle := func(logs []*model.Log) error {
+		for _, log := range logs {
+			received = append(received, log.ID)
+		}
+
+		switch len(received) {
+		case 2:
+			// 前回の最新と同時刻のログ（c）と、Limit を超える件数のログを追加する
+			store.add("c", base.Add(time.Second))
+			store.add("e", base.Add(3*time.Second))
+			store.add("d", base.Add(2*time.Second))
+		case 5:
+			cancel()
+		}
+
+		return nil
+	}
+
+	query := model.LogQuery{Service: "", Level: "", StartTime: base, EndTime: time.Time{}, Limit: 2, Offset: 0}
+	err := client.Follow(ctx, store, query, time.Millisecond, handle, func(err error) { require.NoError(t, err) })
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"a", "b", "c", "d", "e"}, received)
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/KeitaShimura/logs-collector-client/internal/model"
@@ -20,8 +19,9 @@ type GRPCClient struct {
 }
 
 // NewGRPCClient は指定されたエンドポイントに接続する GRPCClient を作成する
-func NewGRPCClient(endpoint string) (*GRPCClient, error) {
-	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// 接続時の認証情報は grpc.WithTransportCredentials などの opts で指定する
+func NewGRPCClient(endpoint string, opts ...grpc.DialOption) (*GRPCClient, error) {
+	conn, err := grpc.NewClient(endpoint, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create gRPC connection: %w", err)
 	}
@@ -32,16 +32,18 @@ func NewGRPCClient(endpoint string) (*GRPCClient, error) {
 }
 
 // Close は gRPC 接続をクローズする
-func (c *GRPCClient) Close() {
-	c.conn.Close()
+func (c *GRPCClient) Close() error {
+	if err := c.conn.Close(); err != nil {
+		return fmt.Errorf("failed to close gRPC connection: %w", err)
+	}
+
+	return nil
 }
 
 // SendLog はログを gRPC API 経由で送信する
 func (c *GRPCClient) SendLog(ctx context.Context, log *model.Log) error {
-	// 文字列の timestamp を protobuf の Timestamp 型に変換
-	timestamp, err := parseTimestamp(log.Timestamp)
-	if err != nil {
-		return fmt.Errorf("failed to parse timestamp: %w", err)
+	if err := prepareLog(log); err != nil {
+		return err
 	}
 
 	// gRPC のリクエストを構築
@@ -49,8 +51,8 @@ func (c *GRPCClient) SendLog(ctx context.Context, log *model.Log) error {
 		Log: &pb.Log{
 			Id:        log.ID,
 			TraceId:   log.TraceID,
-			Timestamp: timestamp,
-			Level:     log.Level,
+			Timestamp: timestamppb.New(log.Timestamp),
+			Level:     string(log.Level),
 			Service:   log.Service,
 			Message:   log.Message,
 			Metadata:  log.Metadata,
@@ -59,28 +61,44 @@ func (c *GRPCClient) SendLog(ctx context.Context, log *model.Log) error {
 
 	// リクエスト送信
 	if _, err := c.client.SendLog(ctx, req); err != nil {
-		return fmt.Errorf("failed to send log via gRPC: %w", err)
+		return newGRPCError(OperationSendLog, err)
 	}
 
 	return nil
 }
 
 // GetLogs は指定された条件でログを gRPC API 経由で取得する
-func (c *GRPCClient) GetLogs(ctx context.Context, service, level string, limit, offset int32) ([]*model.Log, error) {
-	// リクエスト構築
+func (c *GRPCClient) GetLogs(ctx context.Context, query *model.LogQuery) ([]*model.Log, error) {
+	// リクエスト構築（未指定の条件は nil のまま送信する）
 	req := &pb.GetLogsRequest{
-		Service:   StringPtr(service),
-		Level:     StringPtr(level),
-		Limit:     limit,
-		Offset:    offset,
+		Service:   nil,
+		Level:     nil,
+		Limit:     query.Limit,
+		Offset:    query.Offset,
 		StartTime: nil,
 		EndTime:   nil,
 	}
 
+	if query.Service != "" {
+		req.Service = StringPtr(query.Service)
+	}
+
+	if query.Level != "" {
+		req.Level = StringPtr(string(query.Level))
+	}
+
+	if !query.StartTime.IsZero() {
+		req.StartTime = timestamppb.New(query.StartTime)
+	}
+
+	if !query.EndTime.IsZero() {
+		req.EndTime = timestamppb.New(query.EndTime)
+	}
+
 	// リクエスト送信
 	resp, err := c.client.GetLogs(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get logs via gRPC: %w", err)
+		return nil, newGRPCError(OperationGetLogs, err)
 	}
 
 	// 結果を model.Log にマッピング
@@ -91,8 +109,8 @@ func (c *GRPCClient) GetLogs(ctx context.Context, service, level string, limit,
 		logs = append(logs, &model.Log{
 			ID:        protoLog.GetId(),
 			TraceID:   protoLog.GetTraceId(),
-			Timestamp: formatTimestamp(protoLog.GetTimestamp()),
-			Level:     protoLog.GetLevel(),
+			Timestamp: asTime(protoLog.GetTimestamp()),
+			Level:     model.Level(protoLog.GetLevel()),
 			Service:   protoLog.GetService(),
 			Message:   protoLog.GetMessage(),
 			Metadata:  protoLog.GetMetadata(),
@@ -107,21 +125,11 @@ func StringPtr(s string) *string {
 	return &s
 }
 
-// parseTimestamp は RFC3339 フォーマットの文字列を protobuf の Timestamp に変換する
-func parseTimestamp(ts string) (*timestamppb.Timestamp, error) {
-	t, err := time.Parse(time.RFC3339, ts)
-	if err != nil {
-		return nil, fmt.Errorf("invalid timestamp format: %w", err)
-	}
-
-	return timestamppb.New(t), nil
-}
-
-// formatTimestamp は protobuf の Timestamp を RFC3339 文字列に変換する
-func formatTimestamp(ts *timestamppb.Timestamp) string {
+// asTime は protobuf の Timestamp を time.Time に変換する（未設定の場合はゼロ値）
+func asTime(ts *timestamppb.Timestamp) time.Time {
 	if ts == nil {
-		return ""
+		return time.Time{}
 	}
 
-	return ts.AsTime().Format(time.RFC3339)
+	return ts.AsTime()
 }
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// ページ送りに関するエラー
+var (
+	ErrInvalidPageSize   = errors.New("page size must be positive")
+	ErrMaxResultsReached = errors.New("reached maximum number of results")
+)
+
+// PageOptions は GetLogs をページ単位で繰り返す際の設定を保持する構造体
+type PageOptions struct {
+	PageSize   int32 // 1 回の GetLogs で取得する件数
+	MaxResults int   // 取得件数の上限（0 以下は無制限）
+}
+
+// All は query の条件に一致するログを、ページを進めながら最後まで 1 件ずつ返すイテレーターを返す
+// query.Offset を開始位置とし、query.Limit の代わりに PageSize 件ずつ取得する
+// 取得に失敗した場合、または MaxResults を超えるログがある場合はエラーを返して終了する
+func All(ctx context.Context, cli Client, query model.LogQuery, opts PageOptions) iter.Seq2[*model.Log, error] {
+	return func(yield func(*model.Log, error) bool) {
+		if opts.PageSize <= 0 {
+			yield(nil, ErrInvalidPageSize)
+
+			return
+		}
+
+		query.Limit = opts.PageSize
+		count := 0
+
+		for {
+			page, err := cli.GetLogs(ctx, &query)
+			if err != nil {
+				yield(nil, err)
+
+				return
+			}
+
+			for _, log := range page {
+				if opts.MaxResults > 0 && count >= opts.MaxResults {
+					yield(nil, ErrMaxResultsReached)
+
+					return
+				}
+
+				if !yield(log, nil) {
+					return
+				}
+
+				count++
+			}
+
+			// 件数が PageSize に満たないページが最後のページ
+			if len(page) < int(opts.PageSize) {
+				return
+			}
+
+			query.Offset += int32(len(page)) //nolint:gosec // 件数は PageSize（int32）以下
+		}
+	}
+}
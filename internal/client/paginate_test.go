@@ -0,0 +1,57 @@
+package client_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// collect はイテレーターから取得したログの ID と最後のエラーを返す
+func collect(cli client.Client, opts client.PageOptions) ([]string, error) {
+	query := model.LogQuery{Service: "", Level: "", StartTime: time.Time{}, EndTime: time.Time{}, Limit: 0, Offset: 0}
+	ids := make([]string, 0)
+
+	for log, err := range client.All(context.Background(), cli, query, opts) {
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, log.ID)
+	}
+
+	return ids, nil
+}
+
+// TestAll はページを進めて全件を取得し、上限を超える場合はエラーになることを検証する
+func TestAll(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &storeClient{mutex: sync.Mutex{}, logs: nil}
+
+	for i, id := range []string{"a", "b", "c", "d", "e"} {
+		store.add(id, base.Add(time.Duration(i)*time.Second))
+	}
+
+	ids, err := collect(store, client.PageOptions{PageSize: 2, MaxResults: 0})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c", "d", "e"}, ids)
+
+	// 上限と同じ件数しかない場合はエラーにならない
+	ids, err = collect(store, client.PageOptions{PageSize: 5, MaxResults: 5})
+	require.NoError(t, err)
+	require.Len(t, ids, 5)
+
+	ids, err = collect(store, client.PageOptions{PageSize: 2, MaxResults: 3})
+	require.ErrorIs(t, err, client.ErrMaxResultsReached)
+	require.Equal(t, []string{"a", "b", "c"}, ids)
+
+	_, err = collect(store, client.PageOptions{PageSize: 0, MaxResults: 0})
+	require.ErrorIs(t, err, client.ErrInvalidPageSize)
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -13,17 +12,54 @@ import (
 	"github.com/KeitaShimura/logs-collector-client/internal/model"
 )
 
-// ErrUnexpectedHTTPStatus は、想定外の HTTP ステータスが返された場合のエラー
-var ErrUnexpectedHTTPStatus = errors.New("unexpected HTTP status")
+// checkStatus はレスポンスが 200 OK でなければ、ボディの内容を含む Error を返す
+func checkStatus(operation string, res *http.Response) error {
+	if res.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	return newHTTPError(operation, res)
+}
 
 // RESTClient は、ログ送信・取得を行う REST API クライアント
 type RESTClient struct {
-	Endpoint string // REST API のエンドポイント（例: http://localhost:8080）
+	Endpoint   string         // REST API のエンドポイント（例: http://localhost:8080）
+	HTTPClient *http.Client   // リクエストの送信に使用する HTTP クライアント
+	Auth       *Authenticator // Authorization ヘッダーを生成する（nil の場合は付与しない）
+}
+
+// RESTOption は RESTClient のオプション設定用関数
+type RESTOption func(*RESTClient)
+
+// WithHTTPClient はリクエストの送信に使用する HTTP クライアントを設定する
+func WithHTTPClient(httpClient *http.Client) RESTOption {
+	return func(c *RESTClient) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithAuthenticator はリクエストに Authorization ヘッダーを付与する Authenticator を設定する
+func WithAuthenticator(auth *Authenticator) RESTOption {
+	return func(c *RESTClient) {
+		c.Auth = auth
+	}
 }
 
 // NewRESTClient は、指定されたエンドポイントで RESTClient を初期化する
-func NewRESTClient(endpoint string) *RESTClient {
-	return &RESTClient{Endpoint: endpoint}
+// HTTP クライアントを指定しない場合は http.DefaultClient を使用する
+func NewRESTClient(endpoint string, options ...RESTOption) *RESTClient {
+	client := &RESTClient{Endpoint: endpoint, HTTPClient: http.DefaultClient, Auth: nil}
+
+	for _, option := range options {
+		option(client)
+	}
+
+	return client
+}
+
+// Close は RESTClient が保持するリソースを解放する（現状は解放対象なし）
+func (c *RESTClient) Close() error {
+	return nil
 }
 
 // sendLogRequest は POST /api/logs に送信するリクエストボディの構造体
@@ -34,19 +70,39 @@ type sendLogRequest struct {
 
 // SendLog はログデータを REST API に POST で送信する
 func (c *RESTClient) SendLog(ctx context.Context, log *model.Log) error {
+	if err := prepareLog(log); err != nil {
+		return err
+	}
+
 	// リクエストボディ構造に変換
 	bodyStruct := sendLogRequest{Log: log}
 
+	return c.post(ctx, OperationSendLog, "/api/logs", bodyStruct)
+}
+
+// SendLogs は複数のログを JSON 配列として REST API に POST で送信する
+func (c *RESTClient) SendLogs(ctx context.Context, logs []*model.Log) error {
+	for i, log := range logs {
+		if err := prepareLog(log); err != nil {
+			return fmt.Errorf("log %d/%d: %w", i+1, len(logs), err)
+		}
+	}
+
+	return c.post(ctx, OperationSendLogs, "/api/logs/batch", logs)
+}
+
+// post は body を JSON にシリアライズして指定パスに POST する
+func (c *RESTClient) post(ctx context.Context, operation, path string, body any) error {
 	// JSON にシリアライズ
-	body, err := json.Marshal(bodyStruct)
+	encoded, err := json.Marshal(body)
 	if err != nil {
 		return fmt.Errorf("failed to marshal log: %w", err)
 	}
 
 	// POST リクエスト作成
-	url := c.Endpoint + "/api/logs"
+	url := c.Endpoint + path
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(encoded))
 	if err != nil {
 		return fmt.Errorf("failed to create HTTP request: %w", err)
 	}
@@ -55,29 +111,60 @@ func (c *RESTClient) SendLog(ctx context.Context, log *model.Log) error {
 	req.Header.Set("Content-Type", "application/json")
 
 	// リクエスト送信
-	res, err := http.DefaultClient.Do(req)
+	res, err := c.do(operation, req)
 	if err != nil {
-		return fmt.Errorf("HTTP request failed: %w", err)
+		return err
 	}
 	defer res.Body.Close()
 
 	// ステータスコード確認
-	if res.StatusCode != http.StatusOK {
-		return fmt.Errorf("%w: %s", ErrUnexpectedHTTPStatus, res.Status)
+	return checkStatus(operation, res)
+}
+
+// do は認証情報を付与してリクエストを送信する
+// レスポンスを受け取る前に失敗した場合は Error を返す
+func (c *RESTClient) do(operation string, req *http.Request) (*http.Response, error) {
+	if c.Auth != nil {
+		header, err := c.Auth.Header(req.Context())
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", header)
 	}
 
-	return nil
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, newRequestError(operation, err)
+	}
+
+	return res, nil
 }
 
 // GetLogs は指定された条件に基づいてログを取得する
-// クエリパラメータとして service, level, limit, offset を使用する
-func (c *RESTClient) GetLogs(ctx context.Context, service, level string, limit, offset int32) ([]*model.Log, error) {
-	// クエリパラメータ構築
+// クエリパラメータとして service, level, startTime, endTime, limit, offset を使用する
+func (c *RESTClient) GetLogs(ctx context.Context, query *model.LogQuery) ([]*model.Log, error) {
+	// クエリパラメータ構築（未指定の条件は送信しない）
 	queryParams := url.Values{}
-	queryParams.Set("service", service)
-	queryParams.Set("level", level)
-	queryParams.Set("limit", strconv.Itoa(int(limit)))
-	queryParams.Set("offset", strconv.Itoa(int(offset)))
+
+	if query.Service != "" {
+		queryParams.Set("service", query.Service)
+	}
+
+	if query.Level != "" {
+		queryParams.Set("level", string(query.Level))
+	}
+
+	if !query.StartTime.IsZero() {
+		queryParams.Set("startTime", model.FormatTimestamp(query.StartTime))
+	}
+
+	if !query.EndTime.IsZero() {
+		queryParams.Set("endTime", model.FormatTimestamp(query.EndTime))
+	}
+
+	queryParams.Set("limit", strconv.Itoa(int(query.Limit)))
+	queryParams.Set("offset", strconv.Itoa(int(query.Offset)))
 
 	// リクエスト URL を組み立て
 	reqURL := fmt.Sprintf("%s/api/logs?%s", c.Endpoint, queryParams.Encode())
@@ -89,17 +176,34 @@ func (c *RESTClient) GetLogs(ctx context.Context, service, level string, limit,
 	}
 
 	// リクエスト送信
-	res, err := http.DefaultClient.Do(req)
+	res, err := c.do(OperationGetLogs, req)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		return nil, err
 	}
 	defer res.Body.Close()
 
+	// ステータスコード確認
+	if err := checkStatus(OperationGetLogs, res); err != nil {
+		return nil, err
+	}
+
 	// レスポンスをデコードしてログ配列に変換
-	var logs []*model.Log
-	if err := json.NewDecoder(res.Body).Decode(&logs); err != nil {
+	var entries []json.RawMessage
+	if err := json.NewDecoder(res.Body).Decode(&entries); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	// 1 件の timestamp を解釈できないだけで結果全体を失わないよう、解釈できない値はメタデータに残して返す
+	logs := make([]*model.Log, 0, len(entries))
+
+	for _, entry := range entries {
+		log, err := model.DecodeLogLenient(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		logs = append(logs, log)
+	}
+
 	return logs, nil
 }
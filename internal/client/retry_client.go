@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/url"
+	"time"
+
+	"google.golang.org/grpc/status"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// RetryPolicy は再試行の回数と待機時間の計算方法を保持する構造体
+type RetryPolicy struct {
+	MaxAttempts    int           // 初回を含む最大試行回数（1 以下で再試行なし）
+	InitialBackoff time.Duration // 初回の再試行までの待機時間
+	MaxBackoff     time.Duration // 待機時間の上限（ゆらぎとサーバーが指定した待機時間にも適用する）
+	Multiplier     float64       // 再試行ごとの待機時間の倍率
+	Jitter         float64       // 待機時間に加えるゆらぎの割合（0〜1）
+}
+
+// Backoff は attempt 回目（1 始まり）の失敗後に待機する時間を返す（MaxBackoff を超えない）
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt-1))
+	backoff = math.Min(backoff, float64(p.MaxBackoff))
+
+	// 同時に失敗したクライアントの再試行が集中しないよう ±Jitter の範囲でゆらぎを加える
+	if p.Jitter > 0 {
+		backoff *= 1 + p.Jitter*(2*rand.Float64()-1) //nolint:gosec // 暗号用途ではないため math/rand で十分
+		backoff = math.Min(backoff, float64(p.MaxBackoff))
+	}
+
+	return time.Duration(backoff)
+}
+
+// IsRetryable は err が再試行で回復し得るかを判定し、サーバーが指定した待機時間があれば併せて返す
+// Error の場合は Error.Retryable / Error.RetryAfter に従う
+//   - HTTP: 429 と 5xx は再試行する（Retry-After を優先）。その他の 4xx は再試行しない
+//   - gRPC: Unavailable / DeadlineExceeded / ResourceExhausted は再試行する（RetryInfo を優先）
+//   - 接続エラー・期限切れなどのトランスポート層のエラーは再試行する（証明書の検証の失敗や不正なホスト名など設定の誤りは除く）
+//   - キャンセルは再試行しない。呼び出し元のコンテキストが終了している場合は、RetryingClient が再試行しない
+//   - Error でないコンテキストのキャンセル・期限切れのエラーは再試行しない
+func IsRetryable(err error) (bool, time.Duration) {
+	if err == nil {
+		return false, 0
+	}
+
+	if clientErr := (*Error)(nil); errors.As(err, &clientErr) {
+		return clientErr.Retryable, clientErr.RetryAfter
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false, 0
+	}
+
+	if grpcStatus, ok := status.FromError(err); ok {
+		return isRetryableCode(grpcStatus.Code()), 0
+	}
+
+	if urlErr := (*url.Error)(nil); errors.As(err, &urlErr) {
+		return !isConfigError(err), 0
+	}
+
+	return false, 0
+}
+
+// RetryingClient は Client をラップし、再試行可能なエラーをバックオフしながら再試行するクライアント
+type RetryingClient struct {
+	inner  Client
+	policy RetryPolicy
+}
+
+// NewRetryingClient は inner をラップする RetryingClient を生成する
+func NewRetryingClient(inner Client, policy RetryPolicy) *RetryingClient {
+	return &RetryingClient{inner: inner, policy: policy}
+}
+
+// SendLog はログを送信し、再試行可能なエラーの場合は再試行する
+func (c *RetryingClient) SendLog(ctx context.Context, log *model.Log) error {
+	return c.do(ctx, func() error {
+		return c.inner.SendLog(ctx, log)
+	})
+}
+
+// SendLogs はラップしているクライアントがまとめて送信できる場合はバッチ単位で再試行する
+// そうでない場合は 1 件ずつ送信し、ログごとに再試行する
+func (c *RetryingClient) SendLogs(ctx context.Context, logs []*model.Log) error {
+	batchSender, ok := c.inner.(BatchSender)
+	if !ok {
+		return sendEach(ctx, c, logs)
+	}
+
+	return c.do(ctx, func() error {
+		return batchSender.SendLogs(ctx, logs)
+	})
+}
+
+// GetLogs はログを取得し、再試行可能なエラーの場合は再試行する
+func (c *RetryingClient) GetLogs(ctx context.Context, query *model.LogQuery) ([]*model.Log, error) {
+	var logs []*model.Log
+
+	err := c.do(ctx, func() error {
+		var err error
+
+		logs, err = c.inner.GetLogs(ctx, query)
+
+		return err
+	})
+
+	return logs, err
+}
+
+// Close はラップしているクライアントをクローズする
+func (c *RetryingClient) Close() error {
+	return c.inner.Close() //nolint:wrapcheck // ラップ元のエラーをそのまま返す
+}
+
+// do は call を最大 MaxAttempts 回まで実行する（ctx が終了した後は再試行しない）
+func (c *RetryingClient) do(ctx context.Context, call func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := call()
+
+		retryable, retryAfter := IsRetryable(err)
+		if !retryable || attempt >= c.policy.MaxAttempts || ctx.Err() != nil {
+			if err != nil && attempt > 1 {
+				return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+			}
+
+			return err
+		}
+
+		// サーバーが Retry-After を指定した場合はその時間を優先する
+		// 大きな値で送信が止まり続けないよう MaxBackoff を上限とする
+		delay := c.policy.Backoff(attempt)
+		if retryAfter > 0 {
+			delay = min(retryAfter, c.policy.MaxBackoff)
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return fmt.Errorf("retry aborted: %w", errors.Join(ctx.Err(), err))
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package client_test
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// testRetryPolicy は待機時間を最小限にしたテスト用の再試行ポリシー
+var testRetryPolicy = client.RetryPolicy{ //nolint:gochecknoglobals // テスト用の固定値
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     time.Millisecond,
+	Multiplier:     2,
+	Jitter:         0,
+}
+
+// TestIsRetryable はエラー種別ごとの再試行可否を検証する
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		err        error
+		retryable  bool
+		retryAfter time.Duration
+	}{
+		{"nil", nil, false, 0},
+		{"client error retryable", &client.Error{Transport: client.TransportREST, Operation: client.OperationSendLog, StatusCode: http.StatusServiceUnavailable, Code: codes.Unavailable, Message: "", Details: nil, Retryable: true, RetryAfter: 0, Err: client.ErrUnexpectedHTTPStatus}, true, 0},
+		{"client error with RetryAfter", fmt.Errorf("wrapped: %w", &client.Error{Transport: client.TransportREST, Operation: client.OperationSendLog, StatusCode: http.StatusTooManyRequests, Code: codes.ResourceExhausted, Message: "", Details: nil, Retryable: true, RetryAfter: time.Second, Err: client.ErrUnexpectedHTTPStatus}), true, time.Second},
+		{"client error not retryable", &client.Error{Transport: client.TransportREST, Operation: client.OperationSendLog, StatusCode: http.StatusBadRequest, Code: codes.InvalidArgument, Message: "", Details: nil, Retryable: false, RetryAfter: 0, Err: client.ErrUnexpectedHTTPStatus}, false, 0},
+		{"grpc unavailable", fmt.Errorf("wrapped: %w", status.Error(codes.Unavailable, "down")), true, 0},
+		{"grpc resource exhausted", status.Error(codes.ResourceExhausted, "slow down"), true, 0},
+		{"grpc invalid argument", status.Error(codes.InvalidArgument, "bad"), false, 0},
+		{"context canceled", fmt.Errorf("wrapped: %w", context.Canceled), false, 0},
+		{"connection refused", &url.Error{Op: "Post", URL: "http://localhost:8080", Err: syscall.ECONNREFUSED}, true, 0},
+		{"unknown certificate authority", &url.Error{Op: "Post", URL: "https://localhost:8443", Err: x509.UnknownAuthorityError{Cert: nil}}, false, 0},
+		{"invalid host", fmt.Errorf("wrapped: %w", &url.Error{Op: "parse", URL: "http://local host", Err: url.InvalidHostError(" ")}), false, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			retryable, retryAfter := client.IsRetryable(test.err)
+			require.Equal(t, test.retryable, retryable)
+			require.Equal(t, test.retryAfter, retryAfter)
+		})
+	}
+}
+
+// TestRetryPolicy_Backoff は待機時間が倍率に従って増加し、上限で頭打ちになることを検証する
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+
+	policy := client.RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     300 * time.Millisecond,
+		Multiplier:     2,
+		Jitter:         0,
+	}
+
+	require.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	require.Equal(t, 200*time.Millisecond, policy.Backoff(2))
+	require.Equal(t, 300*time.Millisecond, policy.Backoff(3))
+}
+
+// TestRetryPolicy_BackoffJitter はゆらぎを加えた待機時間も MaxBackoff を超えないことを検証する
+func TestRetryPolicy_BackoffJitter(t *testing.T) {
+	t.Parallel()
+
+	policy := client.RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     300 * time.Millisecond,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+
+	for range 1000 {
+		require.LessOrEqual(t, policy.Backoff(1), 150*time.Millisecond)
+		require.GreaterOrEqual(t, policy.Backoff(1), 50*time.Millisecond)
+		require.LessOrEqual(t, policy.Backoff(5), policy.MaxBackoff)
+	}
+}
+
+// TestRetryingClient_CapsRetryAfter はサーバーが指定した待機時間が MaxBackoff を超える場合、MaxBackoff だけ待機して再試行することを検証する
+func TestRetryingClient_CapsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cli := client.NewRetryingClient(client.NewRESTClient(server.URL), testRetryPolicy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, cli.SendLog(ctx, newLog("a")))
+	require.Equal(t, int32(2), calls.Load())
+}
+
+// TestRetryingClient_RetriesServerErrors は 5xx を再試行し、成功した時点で終了することを検証する
+func TestRetryingClient_RetriesServerErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cli := client.NewRetryingClient(client.NewRESTClient(server.URL), testRetryPolicy)
+
+	require.NoError(t, cli.SendLog(context.Background(), newLog("a")))
+	require.Equal(t, int32(3), calls.Load())
+}
+
+// TestRetryingClient_DoesNotRetryClientErrors は 4xx を再試行しないことを検証する
+func TestRetryingClient_DoesNotRetryClientErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	cli := client.NewRetryingClient(client.NewRESTClient(server.URL), testRetryPolicy)
+
+	err := cli.SendLog(context.Background(), newLog("a"))
+	require.ErrorIs(t, err, client.ErrUnexpectedHTTPStatus)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+// TestRetryingClient_GivesUp は最大試行回数に達したら最後のエラーを返すことを検証する
+func TestRetryingClient_GivesUp(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	cli := client.NewRetryingClient(client.NewRESTClient(server.URL), testRetryPolicy)
+
+	err := cli.SendLogs(context.Background(), []*model.Log{newLog("a"), newLog("b")})
+	require.ErrorIs(t, err, client.ErrUnexpectedHTTPStatus)
+	require.Equal(t, int32(testRetryPolicy.MaxAttempts), calls.Load())
+}
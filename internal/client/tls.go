@@ -0,0 +1,80 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// TLS 設定に関するエラー
+var (
+	ErrIncompleteKeyPair = errors.New("client certificate and key must be specified together")
+	ErrInvalidCABundle   = errors.New("no valid certificates found in CA bundle")
+	ErrInsecureWithTLS   = errors.New("GRPC_INSECURE cannot be combined with TLS settings")
+)
+
+// TLSOptions は接続先の検証とクライアント証明書の設定を保持する構造体
+type TLSOptions struct {
+	CAFile     string // 接続先の証明書を検証する CA バンドル（空の場合はシステムの CA を使用）
+	CertFile   string // mTLS で提示するクライアント証明書
+	KeyFile    string // クライアント証明書の秘密鍵
+	ServerName string // 証明書の検証に使用するサーバー名（空の場合は接続先のホスト名）
+}
+
+// IsZero は TLS の設定が一つも指定されていないかを返す
+func (o TLSOptions) IsZero() bool {
+	return o == TLSOptions{CAFile: "", CertFile: "", KeyFile: "", ServerName: ""}
+}
+
+// NewTLSConfig は TLSOptions から tls.Config を生成する
+// クライアント証明書と秘密鍵はどちらか一方のみの指定を許可しない
+func NewTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	//nolint:exhaustruct // 未指定のフィールドは crypto/tls の既定値を使用する
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: opts.ServerName,
+	}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCABundle, opts.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if (opts.CertFile == "") != (opts.KeyFile == "") {
+		return nil, ErrIncompleteKeyPair
+	}
+
+	if opts.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// newHTTPClient は tlsConfig を使用して HTTPS 接続する http.Client を生成する
+func newHTTPClient(tlsConfig *tls.Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // 既定値は常に *http.Transport
+	transport.TLSClientConfig = tlsConfig
+
+	//nolint:exhaustruct // 未指定のフィールドは net/http の既定値を使用する
+	return &http.Client{
+		Transport: transport,
+	}
+}
@@ -0,0 +1,140 @@
+package client_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+)
+
+// writePEM は DER 形式のデータを PEM として dir に書き出し、そのパスを返す
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Headers: nil, Bytes: der}), 0o600))
+
+	return path
+}
+
+// newClientCertificate はクライアント認証用の自己署名証明書を生成し、証明書と秘密鍵のパスを返す
+func newClientCertificate(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	//nolint:exhaustruct // テストに必要なフィールドのみ指定する
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "logs-collector-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return cert, writePEM(t, dir, "client.crt", "CERTIFICATE", der), writePEM(t, dir, "client.key", "EC PRIVATE KEY", keyDER)
+}
+
+// newHTTPSClient は tlsConfig を使用する RESTClient を生成する
+func newHTTPSClient(endpoint string, tlsConfig *tls.Config) *client.RESTClient {
+	//nolint:exhaustruct // TLS の設定のみ指定する
+	httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+	return client.NewRESTClient(endpoint, client.WithHTTPClient(httpClient))
+}
+
+// TestNewTLSConfig_CAFile は CA バンドルで検証できるサーバーにのみ接続できることを検証する
+func TestNewTLSConfig_CAFile(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// CA を指定しない場合はシステムの CA で検証するため失敗する
+	tlsConfig, err := client.NewTLSConfig(client.TLSOptions{CAFile: "", CertFile: "", KeyFile: "", ServerName: ""})
+	require.NoError(t, err)
+	require.Error(t, newHTTPSClient(server.URL, tlsConfig).SendLog(context.Background(), newLog("untrusted")))
+
+	caFile := writePEM(t, t.TempDir(), "ca.crt", "CERTIFICATE", server.Certificate().Raw)
+
+	tlsConfig, err = client.NewTLSConfig(client.TLSOptions{CAFile: caFile, CertFile: "", KeyFile: "", ServerName: ""})
+	require.NoError(t, err)
+	require.NoError(t, newHTTPSClient(server.URL, tlsConfig).SendLog(context.Background(), newLog("trusted")))
+}
+
+// TestNewTLSConfig_ClientCertificate はクライアント証明書を要求するサーバーに mTLS で接続できることを検証する
+func TestNewTLSConfig_ClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	clientCert, certFile, keyFile := newClientCertificate(t, dir)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	//nolint:exhaustruct // クライアント証明書の検証に必要なフィールドのみ指定する
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	caFile := writePEM(t, dir, "ca.crt", "CERTIFICATE", server.Certificate().Raw)
+
+	// クライアント証明書がない場合はハンドシェイクに失敗する
+	tlsConfig, err := client.NewTLSConfig(client.TLSOptions{CAFile: caFile, CertFile: "", KeyFile: "", ServerName: ""})
+	require.NoError(t, err)
+	require.Error(t, newHTTPSClient(server.URL, tlsConfig).SendLog(context.Background(), newLog("anonymous")))
+
+	tlsConfig, err = client.NewTLSConfig(client.TLSOptions{CAFile: caFile, CertFile: certFile, KeyFile: keyFile, ServerName: ""})
+	require.NoError(t, err)
+	require.NoError(t, newHTTPSClient(server.URL, tlsConfig).SendLog(context.Background(), newLog("mutual")))
+}
+
+// TestNewTLSConfig_Invalid は不正な TLS 設定がエラーになることを検証する
+func TestNewTLSConfig_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, certFile, _ := newClientCertificate(t, dir)
+
+	_, err := client.NewTLSConfig(client.TLSOptions{CAFile: "", CertFile: certFile, KeyFile: "", ServerName: ""})
+	require.ErrorIs(t, err, client.ErrIncompleteKeyPair)
+
+	notPEM := filepath.Join(dir, "ca.txt")
+	require.NoError(t, os.WriteFile(notPEM, []byte("not a certificate"), 0o600))
+
+	_, err = client.NewTLSConfig(client.TLSOptions{CAFile: notPEM, CertFile: "", KeyFile: "", ServerName: ""})
+	require.ErrorIs(t, err, client.ErrInvalidCABundle)
+}
@@ -0,0 +1,228 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/collectortest"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// newTransportClients は偽ログ収集サーバーに接続する gRPC / REST のクライアントを返す
+func newTransportClients(t *testing.T, server *collectortest.Server) map[string]client.Client {
+	t.Helper()
+
+	grpcClient, err := client.NewGRPCClient(server.GRPCTarget(), server.GRPCDialOptions()...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = grpcClient.Close() })
+
+	return map[string]client.Client{
+		client.TransportGRPC: grpcClient,
+		client.TransportREST: client.NewRESTClient(server.URL()),
+	}
+}
+
+// newServiceLog は service / level / timestamp を指定してテスト用のログを生成する
+func newServiceLog(message, service string, level model.Level, timestamp time.Time) *model.Log {
+	return &model.Log{
+		ID:        message,
+		TraceID:   "trace-" + message,
+		Timestamp: timestamp,
+		Level:     level,
+		Service:   service,
+		Message:   message,
+		Metadata:  map[string]string{"env": "test"},
+	}
+}
+
+// TestTransport_SendAndGetLogs は gRPC / REST のどちらでも送信したログを条件で絞り込んで取得できることを検証する
+func TestTransport_SendAndGetLogs(t *testing.T) {
+	t.Parallel()
+
+	server := collectortest.NewServer()
+	t.Cleanup(server.Close)
+
+	base := time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+	for transport, cli := range newTransportClients(t, server) {
+		service := "billing-" + transport
+
+		require.NoError(t, cli.SendLog(context.Background(), newServiceLog(transport+"-1", service, model.LevelInfo, base)))
+		require.NoError(t, cli.SendLog(context.Background(), newServiceLog(transport+"-2", service, model.LevelError, base.Add(time.Nanosecond))))
+		require.NoError(t, cli.SendLog(context.Background(), newServiceLog(transport+"-3", service, "warning", base.Add(time.Second))))
+		require.NoError(t, cli.SendLog(context.Background(), newServiceLog(transport+"-4", "other", model.LevelInfo, base)))
+
+		all, err := cli.GetLogs(context.Background(), &model.LogQuery{Service: service, Level: "", StartTime: time.Time{}, EndTime: time.Time{}, Limit: 10, Offset: 0})
+		require.NoError(t, err, transport)
+		require.Len(t, all, 3, transport)
+		require.Equal(t, base.Add(time.Nanosecond), all[1].Timestamp, transport) // ナノ秒精度が保持される
+		require.Equal(t, model.LevelWarn, all[2].Level, transport)               // 送信前に正規化される
+		require.Equal(t, "test", all[0].Metadata["env"], transport)
+
+		errorLogs, err := cli.GetLogs(context.Background(), &model.LogQuery{Service: service, Level: model.LevelError, StartTime: time.Time{}, EndTime: time.Time{}, Limit: 10, Offset: 0})
+		require.NoError(t, err, transport)
+		require.Len(t, errorLogs, 1, transport)
+		require.Equal(t, transport+"-2", errorLogs[0].Message, transport)
+
+		ranged, err := cli.GetLogs(context.Background(), &model.LogQuery{
+			Service:   service,
+			Level:     "",
+			StartTime: base.Add(time.Nanosecond),
+			EndTime:   base.Add(time.Second),
+			Limit:     10,
+			Offset:    0,
+		})
+		require.NoError(t, err, transport)
+		require.Len(t, ranged, 1, transport)
+		require.Equal(t, transport+"-2", ranged[0].Message, transport)
+
+		paged, err := cli.GetLogs(context.Background(), &model.LogQuery{Service: service, Level: "", StartTime: time.Time{}, EndTime: time.Time{}, Limit: 1, Offset: 2})
+		require.NoError(t, err, transport)
+		require.Len(t, paged, 1, transport)
+		require.Equal(t, transport+"-3", paged[0].Message, transport)
+	}
+
+	require.Len(t, server.Logs(), 8)
+}
+
+// TestTransport_RetryOnFault は一時的な障害を RetryingClient が再試行で回復することを検証する
+func TestTransport_RetryOnFault(t *testing.T) {
+	t.Parallel()
+
+	policy := client.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1, Jitter: 0}
+
+	for _, transport := range []string{client.TransportGRPC, client.TransportREST} {
+		server := collectortest.NewServer()
+		t.Cleanup(server.Close)
+
+		cli := client.NewRetryingClient(newTransportClients(t, server)[transport], policy)
+		server.InjectFault(collectortest.Fault{Method: collectortest.MethodSendLog, Times: 2, Latency: 0, Code: codes.Unavailable, Details: nil, Drop: false})
+
+		require.NoError(t, cli.SendLog(context.Background(), newLog("retried")), transport)
+		require.Len(t, server.Requests(), 3, transport)
+		require.Len(t, server.Logs(), 1, transport)
+
+		// 再試行しても回復しないエラーは 1 回で失敗する
+		server.InjectFault(collectortest.Fault{Method: collectortest.MethodSendLog, Times: 1, Latency: 0, Code: codes.InvalidArgument, Details: nil, Drop: false})
+
+		require.Error(t, cli.SendLog(context.Background(), newLog("rejected")), transport)
+		require.Len(t, server.Requests(), 4, transport)
+	}
+}
+
+// TestTransport_Latency は応答の遅延がコンテキストの期限で打ち切られることを検証する
+func TestTransport_Latency(t *testing.T) {
+	t.Parallel()
+
+	server := collectortest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.InjectFault(collectortest.Fault{Method: collectortest.MethodGetLogs, Times: 0, Latency: time.Second, Code: codes.OK, Details: nil, Drop: false})
+
+	for transport, cli := range newTransportClients(t, server) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+
+		_, err := cli.GetLogs(ctx, &model.LogQuery{Service: "", Level: "", StartTime: time.Time{}, EndTime: time.Time{}, Limit: 10, Offset: 0})
+
+		cancel()
+
+		require.Error(t, err, transport)
+
+		if transport == client.TransportGRPC {
+			require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+		} else {
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+		}
+	}
+}
+
+// TestTransport_DeadlineExceeded は期限切れを通信方式によらず同じコード・再試行可否で返し、
+// 呼び出し元のコンテキストが終了した後は再試行しないことを検証する
+func TestTransport_DeadlineExceeded(t *testing.T) {
+	t.Parallel()
+
+	server := collectortest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.InjectFault(collectortest.Fault{Method: collectortest.MethodGetLogs, Times: 0, Latency: time.Second, Code: codes.OK, Details: nil, Drop: false})
+
+	for transport, inner := range newTransportClients(t, server) {
+		cli := client.NewRetryingClient(inner, client.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1, Jitter: 0})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		before := len(server.Requests())
+
+		_, err := cli.GetLogs(ctx, &model.LogQuery{Service: "", Level: "", StartTime: time.Time{}, EndTime: time.Time{}, Limit: 10, Offset: 0})
+
+		cancel()
+
+		clientErr := (*client.Error)(nil)
+		require.ErrorAs(t, err, &clientErr, transport)
+		require.Equal(t, codes.DeadlineExceeded, clientErr.Code, transport)
+		require.True(t, clientErr.Retryable, transport)
+		require.NotContains(t, err.Error(), "giving up", transport)
+		require.Len(t, server.Requests(), before+1, transport)
+	}
+}
+
+// TestTransport_Drop は成功を返したサーバーがログを保存しなかった場合を再現できることを検証する
+func TestTransport_Drop(t *testing.T) {
+	t.Parallel()
+
+	server := collectortest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.InjectFault(collectortest.Fault{Method: "", Times: 0, Latency: 0, Code: codes.OK, Details: nil, Drop: true})
+
+	for transport, cli := range newTransportClients(t, server) {
+		require.NoError(t, cli.SendLog(context.Background(), newLog("dropped-"+transport)), transport)
+	}
+
+	require.Empty(t, server.Logs())
+
+	server.ClearFaults()
+
+	for transport, cli := range newTransportClients(t, server) {
+		require.NoError(t, cli.SendLog(context.Background(), newLog("kept-"+transport)), transport)
+	}
+
+	require.Len(t, server.Logs(), 2)
+}
+
+// TestTransport_BatchAndAuth は REST のバッチ送信と Authorization ヘッダーがサーバーに届くことを検証する
+func TestTransport_BatchAndAuth(t *testing.T) {
+	t.Parallel()
+
+	server := collectortest.NewServer()
+	t.Cleanup(server.Close)
+
+	auth, err := client.NewAuthenticator(client.AuthOptions{
+		Type:         client.AuthTypeAPIKey,
+		Token:        "secret",
+		TokenFile:    "",
+		TokenCommand: "",
+		TokenTTL:     0,
+	})
+	require.NoError(t, err)
+
+	rest := client.NewRESTClient(server.URL(), client.WithAuthenticator(auth))
+	batching, err := client.NewBatchingClient(rest, client.BatchOptions{MaxCount: 3, MaxBytes: 0, MaxLinger: time.Hour}, nil)
+	require.NoError(t, err)
+
+	for _, message := range []string{"a", "b", "c"} {
+		require.NoError(t, batching.SendLog(context.Background(), newLog(message)))
+	}
+
+	require.NoError(t, batching.Close())
+
+	requests := server.Requests()
+	require.Len(t, requests, 1)
+	require.Equal(t, collectortest.MethodSendLogs, requests[0].Method)
+	require.Equal(t, "ApiKey secret", requests[0].Authorization)
+	require.Len(t, server.Logs(), 3)
+}
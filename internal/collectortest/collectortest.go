@@ -0,0 +1,217 @@
+// Package collectortest は、クライアントのテストに使用するインプロセスの偽ログ収集サーバーを提供する
+// gRPC（bufconn）と REST（httptest）の両方で同じインメモリのストアを公開し、
+// 遅延・エラー・破棄などの障害を注入できる
+package collectortest
+
+import (
+	"context"
+	"maps"
+	"net"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	pb "github.com/KeitaShimura/logs-collector-protos/go/logs/v1"
+)
+
+// bufferSize は bufconn のバッファサイズ
+const bufferSize = 1024 * 1024
+
+// 障害を注入する対象の操作（Fault.Method に指定する値）
+const (
+	MethodSendLog  = "SendLog"  // gRPC の SendLog / REST の POST /api/logs
+	MethodSendLogs = "SendLogs" // REST の POST /api/logs/batch
+	MethodGetLogs  = "GetLogs"  // gRPC の GetLogs / REST の GET /api/logs
+)
+
+// Fault はリクエストに注入する障害を表す構造体
+// Latency の待機後、Code が codes.OK 以外であればエラーを返し、Drop が true であれば成功を返してログを保存しない
+type Fault struct {
+	Method  string          // 対象の操作（空の場合はすべての操作）
+	Times   int             // 適用するリクエスト数（0 以下の場合は ClearFaults まで適用し続ける）
+	Latency time.Duration   // 応答までの遅延
+	Code    codes.Code      // 返すエラー（REST では対応する HTTP ステータスに変換する）
+	Details []proto.Message // エラーに付与する詳細（gRPC では status の details、REST ではレスポンスボディの details）
+	Drop    bool            // 成功を返すがログを保存しない
+}
+
+// Request はサーバーが受け付けたリクエストの記録
+type Request struct {
+	Transport     string // grpc / rest
+	Method        string // MethodSendLog / MethodSendLogs / MethodGetLogs
+	Authorization string // authorization メタデータ / Authorization ヘッダーの値
+}
+
+// Server は gRPC と REST の両方でログの送信・取得を受け付ける偽ログ収集サーバー
+type Server struct {
+	mutex    sync.Mutex // logs / faults / requests を保護する
+	logs     []*model.Log
+	faults   []*Fault
+	requests []Request
+
+	listener   *bufconn.Listener
+	grpcServer *grpc.Server
+	httpServer *httptest.Server
+}
+
+// NewServer は gRPC と REST のサーバーを起動する。終了時は Close を呼び出す
+func NewServer() *Server {
+	server := &Server{
+		mutex:      sync.Mutex{},
+		logs:       nil,
+		faults:     nil,
+		requests:   nil,
+		listener:   bufconn.Listen(bufferSize),
+		grpcServer: grpc.NewServer(),
+		httpServer: nil,
+	}
+
+	pb.RegisterLogServiceServer(server.grpcServer, &grpcService{UnimplementedLogServiceServer: pb.UnimplementedLogServiceServer{}, server: server})
+
+	go server.grpcServer.Serve(server.listener) //nolint:errcheck // Stop 時のエラーは無視する
+
+	server.httpServer = httptest.NewServer(server.restHandler())
+
+	return server
+}
+
+// Close は gRPC と REST のサーバーを停止する
+func (s *Server) Close() {
+	s.grpcServer.Stop()
+	s.httpServer.Close()
+}
+
+// GRPCTarget は gRPC クライアントの接続先を返す（GRPCDialOptions と組み合わせて使用する）
+func (s *Server) GRPCTarget() string {
+	return "passthrough:///bufconn"
+}
+
+// GRPCDialOptions は bufconn 経由で平文接続するためのダイアルオプションを返す
+func (s *Server) GRPCDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+}
+
+// URL は REST API のベース URL を返す（例: http://127.0.0.1:12345）
+func (s *Server) URL() string {
+	return s.httpServer.URL
+}
+
+// Add はログをストアに直接追加する（取得のテストの準備に使用する）
+func (s *Server) Add(logs ...*model.Log) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, log := range logs {
+		s.logs = append(s.logs, cloneLog(log))
+	}
+}
+
+// Logs は保存されているログを受信順に返す
+func (s *Server) Logs() []*model.Log {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	logs := make([]*model.Log, 0, len(s.logs))
+	for _, log := range s.logs {
+		logs = append(logs, cloneLog(log))
+	}
+
+	return logs
+}
+
+// Requests は受け付けたリクエストを受信順に返す（注入した障害で失敗したリクエストを含む）
+func (s *Server) Requests() []Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+// InjectFault は以降のリクエストに障害を注入する
+// 複数の障害を注入した場合は、対象の操作に一致する最初の障害を適用する
+func (s *Server) InjectFault(fault Fault) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults は注入した障害をすべて取り除く
+func (s *Server) ClearFaults() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.faults = nil
+}
+
+// begin はリクエストを記録し、適用する障害を返す（障害がない場合はゼロ値）
+// 障害の遅延はここで待機する
+func (s *Server) begin(ctx context.Context, request Request) (Fault, error) {
+	s.mutex.Lock()
+	s.requests = append(s.requests, request)
+	fault := s.takeFault(request.Method)
+	s.mutex.Unlock()
+
+	if fault.Latency > 0 {
+		timer := time.NewTimer(fault.Latency)
+		defer timer.Stop()
+
+		select {
+		case <-ctx.Done():
+			return fault, ctx.Err() //nolint:wrapcheck // キャンセルをそのまま返す
+		case <-timer.C:
+		}
+	}
+
+	return fault, nil
+}
+
+// takeFault は method に一致する最初の障害を取り出し、適用回数を減らす（mutex を取得済みで呼び出す）
+func (s *Server) takeFault(method string) Fault {
+	for i, fault := range s.faults {
+		if fault.Method != "" && fault.Method != method {
+			continue
+		}
+
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = slices.Delete(s.faults, i, i+1)
+			}
+		}
+
+		return *fault
+	}
+
+	return Fault{Method: "", Times: 0, Latency: 0, Code: codes.OK, Details: nil, Drop: false}
+}
+
+// store はログを保存する（drop が true の場合は保存しない）
+func (s *Server) store(logs []*model.Log, drop bool) {
+	if drop {
+		return
+	}
+
+	s.Add(logs...)
+}
+
+// cloneLog はログを複製する（呼び出し側による変更がストアに影響しないようにする）
+func cloneLog(log *model.Log) *model.Log {
+	clone := *log
+	clone.Metadata = maps.Clone(log.Metadata)
+
+	return &clone
+}
@@ -0,0 +1,67 @@
+package collectortest_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/collectortest"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// TestServer_FaultTargeting は障害が対象の操作に指定回数だけ適用され、REST では HTTP ステータスに変換されることを検証する
+func TestServer_FaultTargeting(t *testing.T) {
+	t.Parallel()
+
+	server := collectortest.NewServer()
+	t.Cleanup(server.Close)
+
+	server.InjectFault(collectortest.Fault{Method: collectortest.MethodGetLogs, Times: 1, Latency: 0, Code: codes.ResourceExhausted, Details: nil, Drop: false})
+
+	get := func() int {
+		res, err := http.Get(server.URL() + "/api/logs?limit=5") //nolint:noctx // テスト用のリクエスト
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+
+		return res.StatusCode
+	}
+
+	require.Equal(t, http.StatusTooManyRequests, get())
+	require.Equal(t, http.StatusOK, get())
+
+	requests := server.Requests()
+	require.Len(t, requests, 2)
+	require.Equal(t, "rest", requests[0].Transport)
+	require.Equal(t, collectortest.MethodGetLogs, requests[0].Method)
+}
+
+// TestServer_Add は Add で追加したログが複製して保存されることを検証する
+func TestServer_Add(t *testing.T) {
+	t.Parallel()
+
+	server := collectortest.NewServer()
+	t.Cleanup(server.Close)
+
+	log := &model.Log{
+		ID:        "id",
+		TraceID:   "",
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:     model.LevelInfo,
+		Service:   "billing",
+		Message:   "paid",
+		Metadata:  map[string]string{"env": "dev"},
+	}
+
+	server.Add(log)
+	log.Metadata["env"] = "changed"
+
+	require.Equal(t, "dev", server.Logs()[0].Metadata["env"])
+
+	res, err := http.Get(server.URL() + "/api/logs?startTime=invalid") //nolint:noctx // テスト用のリクエスト
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
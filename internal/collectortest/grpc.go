@@ -0,0 +1,132 @@
+package collectortest
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	pb "github.com/KeitaShimura/logs-collector-protos/go/logs/v1"
+)
+
+// transportGRPC は gRPC で受け付けたリクエストの Request.Transport の値
+const transportGRPC = "grpc"
+
+// grpcService は pb.LogServiceServer の実装
+type grpcService struct {
+	pb.UnimplementedLogServiceServer
+
+	server *Server
+}
+
+// SendLog はログをストアに保存する
+func (g *grpcService) SendLog(ctx context.Context, req *pb.SendLogRequest) (*pb.SendLogResponse, error) {
+	fault, err := g.begin(ctx, MethodSendLog)
+	if err != nil {
+		return nil, err
+	}
+
+	g.server.store([]*model.Log{fromProto(req.GetLog())}, fault.Drop)
+
+	return &pb.SendLogResponse{}, nil
+}
+
+// GetLogs は条件に一致するログを返す
+func (g *grpcService) GetLogs(ctx context.Context, req *pb.GetLogsRequest) (*pb.GetLogsResponse, error) {
+	fault, err := g.begin(ctx, MethodGetLogs)
+	if err != nil {
+		return nil, err
+	}
+
+	if fault.Drop {
+		return &pb.GetLogsResponse{}, nil
+	}
+
+	logs := g.server.query(&model.LogQuery{
+		Service:   req.GetService(),
+		Level:     model.Level(req.GetLevel()),
+		StartTime: asTime(req.GetStartTime()),
+		EndTime:   asTime(req.GetEndTime()),
+		Limit:     req.GetLimit(),
+		Offset:    req.GetOffset(),
+	})
+
+	protoLogs := make([]*pb.Log, 0, len(logs))
+	for _, log := range logs {
+		protoLogs = append(protoLogs, toProto(log))
+	}
+
+	return &pb.GetLogsResponse{Logs: protoLogs}, nil
+}
+
+// begin はリクエストを記録し、注入された障害があれば gRPC のエラーとして返す
+func (g *grpcService) begin(ctx context.Context, method string) (Fault, error) {
+	authorization := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+		authorization = md.Get("authorization")[0]
+	}
+
+	fault, err := g.server.begin(ctx, Request{Transport: transportGRPC, Method: method, Authorization: authorization})
+	if err != nil {
+		return fault, status.FromContextError(err).Err() //nolint:wrapcheck // gRPC のステータスとして返す
+	}
+
+	if fault.Code != codes.OK {
+		faultStatus := status.Newf(fault.Code, "injected fault: %s", fault.Code).Proto()
+
+		for _, detail := range fault.Details {
+			if wrapped, err := anypb.New(detail); err == nil {
+				faultStatus.Details = append(faultStatus.Details, wrapped)
+			}
+		}
+
+		return fault, status.ErrorProto(faultStatus) //nolint:wrapcheck // gRPC のステータスとして返す
+	}
+
+	return fault, nil
+}
+
+// fromProto は protobuf のログを model.Log に変換する
+func fromProto(log *pb.Log) *model.Log {
+	return &model.Log{
+		ID:        log.GetId(),
+		TraceID:   log.GetTraceId(),
+		Timestamp: asTime(log.GetTimestamp()),
+		Level:     model.Level(log.GetLevel()),
+		Service:   log.GetService(),
+		Message:   log.GetMessage(),
+		Metadata:  log.GetMetadata(),
+	}
+}
+
+// toProto は model.Log を protobuf のログに変換する
+func toProto(log *model.Log) *pb.Log {
+	var timestamp *timestamppb.Timestamp
+	if !log.Timestamp.IsZero() {
+		timestamp = timestamppb.New(log.Timestamp)
+	}
+
+	return &pb.Log{
+		Id:        log.ID,
+		TraceId:   log.TraceID,
+		Timestamp: timestamp,
+		Level:     string(log.Level),
+		Service:   log.Service,
+		Message:   log.Message,
+		Metadata:  log.Metadata,
+	}
+}
+
+// asTime は protobuf の Timestamp を time.Time に変換する（未設定の場合はゼロ値）
+func asTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+
+	return ts.AsTime()
+}
@@ -0,0 +1,50 @@
+package collectortest
+
+import (
+	"slices"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// query は条件に一致するログを timestamp の昇順（同時刻は受信順）で返す
+//   - Service / Level: 完全一致（空の場合は条件なし）
+//   - StartTime: 指定時刻以降（同時刻を含む）、EndTime: 指定時刻より前（ゼロ値の場合は条件なし）
+//   - Offset 件を読み飛ばし、最大 Limit 件を返す（Limit が 0 以下の場合は上限なし）
+func (s *Server) query(query *model.LogQuery) []*model.Log {
+	matched := make([]*model.Log, 0)
+
+	for _, log := range s.Logs() {
+		if matches(log, query) {
+			matched = append(matched, log)
+		}
+	}
+
+	slices.SortStableFunc(matched, func(a, b *model.Log) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	offset := min(max(int(query.Offset), 0), len(matched))
+	matched = matched[offset:]
+
+	if query.Limit > 0 && int(query.Limit) < len(matched) {
+		matched = matched[:query.Limit]
+	}
+
+	return matched
+}
+
+// matches はログが検索条件に一致するかを判定する
+func matches(log *model.Log, query *model.LogQuery) bool {
+	switch {
+	case query.Service != "" && log.Service != query.Service:
+		return false
+	case query.Level != "" && log.Level != query.Level:
+		return false
+	case !query.StartTime.IsZero() && log.Timestamp.Before(query.StartTime):
+		return false
+	case !query.EndTime.IsZero() && !log.Timestamp.Before(query.EndTime):
+		return false
+	default:
+		return true
+	}
+}
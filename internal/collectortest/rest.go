@@ -0,0 +1,204 @@
+package collectortest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// transportREST は REST で受け付けたリクエストの Request.Transport の値
+const transportREST = "rest"
+
+// errInvalidParameter は、クエリパラメータが不正な場合のエラー
+var errInvalidParameter = errors.New("invalid parameter")
+
+// httpStatuses は注入するエラーの gRPC コードと HTTP ステータスの対応表（未定義のコードは 500 とする）
+var httpStatuses = map[codes.Code]int{
+	codes.InvalidArgument:   http.StatusBadRequest,
+	codes.Unauthenticated:   http.StatusUnauthorized,
+	codes.PermissionDenied:  http.StatusForbidden,
+	codes.NotFound:          http.StatusNotFound,
+	codes.AlreadyExists:     http.StatusConflict,
+	codes.ResourceExhausted: http.StatusTooManyRequests,
+	codes.Unimplemented:     http.StatusNotImplemented,
+	codes.Unavailable:       http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:  http.StatusGatewayTimeout,
+}
+
+// restHandler は REST API のハンドラーを返す
+//   - POST /api/logs: {"log": {...}} 形式のログを 1 件保存する
+//   - POST /api/logs/batch: ログの JSON 配列を保存する
+//   - GET /api/logs: service / level / startTime / endTime / limit / offset に一致するログを JSON 配列で返す
+func (s *Server) restHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/logs", s.handleSendLog)
+	mux.HandleFunc("POST /api/logs/batch", s.handleSendLogs)
+	mux.HandleFunc("GET /api/logs", s.handleGetLogs)
+
+	return mux
+}
+
+// handleSendLog は POST /api/logs を処理する
+func (s *Server) handleSendLog(w http.ResponseWriter, r *http.Request) {
+	fault, ok := s.beginREST(w, r, MethodSendLog)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Log *model.Log `json:"log"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Log == nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+
+		return
+	}
+
+	s.store([]*model.Log{body.Log}, fault.Drop)
+	writeJSON(w, struct{}{})
+}
+
+// handleSendLogs は POST /api/logs/batch を処理する
+func (s *Server) handleSendLogs(w http.ResponseWriter, r *http.Request) {
+	fault, ok := s.beginREST(w, r, MethodSendLogs)
+	if !ok {
+		return
+	}
+
+	var logs []*model.Log
+	if err := json.NewDecoder(r.Body).Decode(&logs); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+
+		return
+	}
+
+	s.store(logs, fault.Drop)
+	writeJSON(w, struct{}{})
+}
+
+// handleGetLogs は GET /api/logs を処理する
+func (s *Server) handleGetLogs(w http.ResponseWriter, r *http.Request) {
+	fault, ok := s.beginREST(w, r, MethodGetLogs)
+	if !ok {
+		return
+	}
+
+	query, err := parseQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	logs := []*model.Log{}
+	if !fault.Drop {
+		logs = s.query(query)
+	}
+
+	writeJSON(w, logs)
+}
+
+// beginREST はリクエストを記録し、注入された障害があればエラーレスポンスを書き込んで false を返す
+func (s *Server) beginREST(w http.ResponseWriter, r *http.Request, method string) (Fault, bool) {
+	request := Request{Transport: transportREST, Method: method, Authorization: r.Header.Get("Authorization")}
+
+	fault, err := s.begin(r.Context(), request)
+	if err != nil {
+		return fault, false
+	}
+
+	if fault.Code != codes.OK {
+		writeError(w, httpStatus(fault.Code), "injected fault: "+fault.Code.String(), fault.Details...)
+
+		return fault, false
+	}
+
+	return fault, true
+}
+
+// parseQuery はクエリパラメータを検索条件に変換する
+func parseQuery(r *http.Request) (*model.LogQuery, error) {
+	params := r.URL.Query()
+	query := &model.LogQuery{
+		Service:   params.Get("service"),
+		Level:     model.Level(params.Get("level")),
+		StartTime: time.Time{},
+		EndTime:   time.Time{},
+		Limit:     0,
+		Offset:    0,
+	}
+
+	for name, target := range map[string]*int32{"limit": &query.Limit, "offset": &query.Offset} {
+		if value := params.Get(name); value != "" {
+			number, err := strconv.ParseInt(value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s=%q", errInvalidParameter, name, value)
+			}
+
+			*target = int32(number)
+		}
+	}
+
+	for name, target := range map[string]*time.Time{"startTime": &query.StartTime, "endTime": &query.EndTime} {
+		if value := params.Get(name); value != "" {
+			parsed, err := model.ParseTimestamp(value, nil)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s=%q", errInvalidParameter, name, value)
+			}
+
+			*target = parsed
+		}
+	}
+
+	return query, nil
+}
+
+// httpStatus は gRPC のコードに対応する HTTP ステータスを返す
+func httpStatus(code codes.Code) int {
+	if status, ok := httpStatuses[code]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+// writeJSON は body を JSON として書き込む
+func writeJSON(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError はエラーレスポンスを書き込む
+// details は {"error": message, "details": [...]} の形式で、型名（@type）を含む JSON として書き込む
+func writeError(w http.ResponseWriter, status int, message string, details ...proto.Message) {
+	body := struct {
+		Error   string            `json:"error"`
+		Details []json.RawMessage `json:"details,omitempty"`
+	}{Error: message, Details: nil}
+
+	for _, detail := range details {
+		wrapped, err := anypb.New(detail)
+		if err != nil {
+			continue
+		}
+
+		if encoded, err := protojson.Marshal(wrapped); err == nil {
+			body.Details = append(body.Details, encoded)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
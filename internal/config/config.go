@@ -1,25 +1,108 @@
 package config
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
+	"os"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 )
 
-// Config は、環境変数から読み込まれるアプリケーション設定を保持する構造体
+// Config は、環境変数と設定ファイルから読み込まれるアプリケーション設定を保持する構造体
+// secret タグを付与したフィールドは config show で値を伏せて表示する
 type Config struct {
+	Transport     string `env:"TRANSPORT"      envDefault:"grpc"`
 	GRPCEndpoint  string `env:"GRPC_ENDPOINT"  envDefault:"localhost:50051"`
 	RESTEndpoint  string `env:"REST_ENDPOINT"  envDefault:"http://localhost:8080"`
 	DefaultLimit  int    `env:"DEFAULT_LIMIT"  envDefault:"10"`
 	DefaultOffset int    `env:"DEFAULT_OFFSET" envDefault:"0"`
+
+	// 入力されたタイムスタンプの解釈に使用するレイアウト（; 区切り。Go の時刻レイアウトまたは unix、空の場合は既定のレイアウト）
+	TimestampLayouts []string `env:"TIMESTAMP_LAYOUTS" envSeparator:";"`
+
+	// 全件取得（query --all）の設定
+	QueryPageSize   int `env:"QUERY_PAGE_SIZE"   envDefault:"100"`
+	QueryMaxResults int `env:"QUERY_MAX_RESULTS" envDefault:"10000"`
+
+	// TLS の設定（gRPC と https の REST に適用する。GRPCInsecure が true の場合のみ gRPC を平文で接続する）
+	GRPCInsecure  bool   `env:"GRPC_INSECURE"   envDefault:"false"`
+	TLSCAFile     string `env:"TLS_CA_FILE"`
+	TLSCertFile   string `env:"TLS_CERT_FILE"`
+	TLSKeyFile    string `env:"TLS_KEY_FILE"`
+	TLSServerName string `env:"TLS_SERVER_NAME"`
+
+	// 認証の設定（AUTH_TOKEN / AUTH_TOKEN_FILE / AUTH_TOKEN_COMMAND のいずれか一つを指定する）
+	AuthType         string        `env:"AUTH_TYPE"          envDefault:"bearer"`
+	AuthToken        string        `env:"AUTH_TOKEN"         secret:"true"`
+	AuthTokenFile    string        `env:"AUTH_TOKEN_FILE"`
+	AuthTokenCommand string        `env:"AUTH_TOKEN_COMMAND"`
+	AuthTokenTTL     time.Duration `env:"AUTH_TOKEN_TTL"     envDefault:"5m"`
+
+	// バッチ送信の設定（BatchEnabled が true の場合のみ使用）
+	BatchEnabled   bool          `env:"BATCH_ENABLED"    envDefault:"false"`
+	BatchMaxCount  int           `env:"BATCH_MAX_COUNT"  envDefault:"100"`
+	BatchMaxBytes  int           `env:"BATCH_MAX_BYTES"  envDefault:"1048576"`
+	BatchMaxLinger time.Duration `env:"BATCH_MAX_LINGER" envDefault:"1s"`
+
+	// 再試行の設定（RetryMaxAttempts が 1 以下の場合は再試行しない）
+	RetryMaxAttempts    int           `env:"RETRY_MAX_ATTEMPTS"    envDefault:"3"`
+	RetryInitialBackoff time.Duration `env:"RETRY_INITIAL_BACKOFF" envDefault:"200ms"`
+	RetryMaxBackoff     time.Duration `env:"RETRY_MAX_BACKOFF"     envDefault:"5s"`
+	RetryMultiplier     float64       `env:"RETRY_MULTIPLIER"      envDefault:"2"`
+	RetryJitter         float64       `env:"RETRY_JITTER"          envDefault:"0.2"`
+
+	// ディスクバッファ（WAL）の設定（WALDir が空の場合は使用しない）
+	WALDir           string        `env:"WAL_DIR"`
+	WALMaxBytes      int64         `env:"WAL_MAX_BYTES"      envDefault:"268435456"`
+	WALSegmentBytes  int64         `env:"WAL_SEGMENT_BYTES"  envDefault:"16777216"`
+	WALRetryInterval time.Duration `env:"WAL_RETRY_INTERVAL" envDefault:"5s"`
+	WALDrainTimeout  time.Duration `env:"WAL_DRAIN_TIMEOUT"  envDefault:"10s"`
+
+	// 読み込んだ設定ファイルとプロファイル（読み込んでいない場合は空）
+	File    string `env:"-"`
+	Profile string `env:"-"`
+}
+
+// LoadOptions は設定の読み込み元を指定する構造体
+type LoadOptions struct {
+	Path    string // 設定ファイルのパス（空の場合は LOGS_COLLECTOR_CONFIG、それも空の場合は DefaultPath）
+	Profile string // 使用するプロファイル（空の場合は LOGS_COLLECTOR_PROFILE、それも空の場合は current_profile）
 }
 
-// LoadConfig は、環境変数を読み込んで Config を生成する
+// LoadConfig は、既定の設定ファイルと環境変数を読み込んで Config を生成する
 func LoadConfig() (*Config, error) {
+	return Load(LoadOptions{Path: "", Profile: ""})
+}
+
+// Load は設定ファイルのプロファイルと環境変数を読み込んで Config を生成する
+// 優先順位は 環境変数 > 設定ファイル > 既定値（コマンドのフラグは呼び出し側で上書きする）
+// Path または LOGS_COLLECTOR_CONFIG で指定した設定ファイルが存在しない場合はエラーとする
+func Load(opts LoadOptions) (*Config, error) {
+	path := cmp.Or(opts.Path, os.Getenv(ConfigFileEnv))
+	required := path != ""
+
+	if !required {
+		path = DefaultPath()
+	}
+
+	source, err := readProfile(path, cmp.Or(opts.Profile, os.Getenv(ProfileEnv)), required)
+	if err != nil {
+		return nil, err
+	}
+
+	// 設定ファイルの値を環境変数で上書きした値を読み込む
+	environment := maps.Clone(source.values)
+	maps.Copy(environment, env.ToMap(os.Environ()))
+
 	var cfg Config
-	if err := env.Parse(&cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse env: %w", err)
+	if err := env.ParseWithOptions(&cfg, env.Options{Environment: environment}); err != nil { //nolint:exhaustruct // 未指定のオプションは既定値を使用する
+		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
+	cfg.File = source.path
+	cfg.Profile = source.profile
+
 	return &cfg, nil
 }
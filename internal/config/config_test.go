@@ -0,0 +1,134 @@
+package config_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/config"
+)
+
+// writeConfigFile は設定ファイルを一時ディレクトリに書き出し、そのパスを返す
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+// TestLoad_YAMLProfile は current_profile の値が既定値より、環境変数が設定ファイルより優先されることを検証する
+//
+//nolint:paralleltest // t.Setenv を使用するため並列実行しない
+func TestLoad_YAMLProfile(t *testing.T) {
+	t.Setenv(config.ProfileEnv, "")
+	t.Setenv("GRPC_ENDPOINT", "env:50051")
+
+	path := writeConfigFile(t, "config.yaml", `
+current_profile: staging
+profiles:
+  staging:
+    transport: rest
+    rest_endpoint: https://staging.example.com
+    grpc_endpoint: staging:50051
+    retry_max_attempts: 5
+    batch_max_linger: 2s
+    grpc_insecure: true
+  prod:
+    transport: grpc
+`)
+
+	cfg, err := config.Load(config.LoadOptions{Path: path, Profile: ""})
+	require.NoError(t, err)
+
+	require.Equal(t, "staging", cfg.Profile)
+	require.Equal(t, path, cfg.File)
+	require.Equal(t, "rest", cfg.Transport)
+	require.Equal(t, "https://staging.example.com", cfg.RESTEndpoint)
+	require.Equal(t, "env:50051", cfg.GRPCEndpoint)
+	require.Equal(t, 5, cfg.RetryMaxAttempts)
+	require.Equal(t, 2*time.Second, cfg.BatchMaxLinger)
+	require.True(t, cfg.GRPCInsecure)
+	require.Equal(t, 10, cfg.DefaultLimit)
+}
+
+// TestLoad_TOMLProfile は TOML の設定ファイルから指定したプロファイルを読み込めることを検証する
+//
+//nolint:paralleltest // t.Setenv を使用するため並列実行しない
+func TestLoad_TOMLProfile(t *testing.T) {
+	t.Setenv(config.ProfileEnv, "")
+
+	path := writeConfigFile(t, "config.toml", `
+current_profile = "local"
+
+[profiles.local]
+transport = "grpc"
+
+[profiles.prod]
+transport = "rest"
+default_limit = 50
+retry_jitter = 0.5
+`)
+
+	cfg, err := config.Load(config.LoadOptions{Path: path, Profile: "prod"})
+	require.NoError(t, err)
+
+	require.Equal(t, "prod", cfg.Profile)
+	require.Equal(t, "rest", cfg.Transport)
+	require.Equal(t, 50, cfg.DefaultLimit)
+	require.InDelta(t, 0.5, cfg.RetryJitter, 0)
+}
+
+// TestLoad_Invalid は不正な設定ファイル・プロファイルがエラーになることを検証する
+//
+//nolint:paralleltest // t.Setenv を使用するため並列実行しない
+func TestLoad_Invalid(t *testing.T) {
+	t.Setenv(config.ProfileEnv, "")
+
+	path := writeConfigFile(t, "config.yaml", "profiles:\n  local:\n    grpc_endpiont: localhost:50051\n")
+
+	_, err := config.Load(config.LoadOptions{Path: path, Profile: "local"})
+	require.ErrorIs(t, err, config.ErrUnknownConfigKey)
+
+	_, err = config.Load(config.LoadOptions{Path: path, Profile: "prod"})
+	require.ErrorIs(t, err, config.ErrUnknownProfile)
+
+	_, err = config.Load(config.LoadOptions{Path: filepath.Join(t.TempDir(), "missing.yaml"), Profile: ""})
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// 既定の場所に設定ファイルがない場合は環境変数と既定値のみを使用する
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(config.ConfigFileEnv, "")
+
+	cfg, err := config.Load(config.LoadOptions{Path: "", Profile: ""})
+	require.NoError(t, err)
+	require.Empty(t, cfg.File)
+	require.Equal(t, "file: (none), profile: (none)", cfg.Source())
+}
+
+// TestWriteMasked は秘匿情報を伏せて設定を書き出すことを検証する
+//
+//nolint:paralleltest // t.Setenv を使用するため並列実行しない
+func TestWriteMasked(t *testing.T) {
+	t.Setenv(config.ProfileEnv, "")
+	t.Setenv("AUTH_TOKEN", "super-secret")
+
+	path := writeConfigFile(t, "config.yaml", "current_profile: local\nprofiles:\n  local:\n    transport: rest\n")
+
+	cfg, err := config.Load(config.LoadOptions{Path: path, Profile: ""})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, cfg.WriteMasked(&buf))
+
+	require.Contains(t, buf.String(), "# profile: local\n")
+	require.Equal(t, "file: "+path+", profile: local", cfg.Source())
+	require.Contains(t, buf.String(), "transport: rest\n")
+	require.Contains(t, buf.String(), "auth_token: '********'\n")
+	require.NotContains(t, buf.String(), "super-secret")
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/caarlos0/env/v11"
+	"gopkg.in/yaml.v3"
+)
+
+// 設定ファイルに関するエラー
+var (
+	ErrUnknownProfile       = errors.New("unknown profile")
+	ErrUnknownConfigKey     = errors.New("unknown config key")
+	ErrInvalidConfigValue   = errors.New("config value must be a string, number or boolean")
+	ErrUnsupportedConfigExt = errors.New("config file must be .yaml, .yml or .toml")
+)
+
+// 設定ファイルの場所とプロファイルを指定する環境変数
+const (
+	ConfigFileEnv = "LOGS_COLLECTOR_CONFIG"
+	ProfileEnv    = "LOGS_COLLECTOR_PROFILE"
+)
+
+// defaultProfile は使用するプロファイルが指定されていない場合に使用するプロファイル名
+const defaultProfile = "default"
+
+// fileConfig は設定ファイルの構造
+//
+//	current_profile: local
+//	profiles:
+//	  local:
+//	    transport: grpc
+//	    grpc_endpoint: localhost:50051
+//	    grpc_insecure: true
+//
+// プロファイルのキーは環境変数名を小文字にしたもの
+type fileConfig struct {
+	CurrentProfile string                    `toml:"current_profile" yaml:"current_profile"`
+	Profiles       map[string]map[string]any `toml:"profiles"        yaml:"profiles"`
+}
+
+// DefaultPath は既定の設定ファイルのパス（$XDG_CONFIG_HOME/logs-collector-client/config.yaml）を返す
+// XDG_CONFIG_HOME が未設定の場合は ~/.config を使用する
+func DefaultPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+
+		dir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(dir, "logs-collector-client", "config.yaml")
+}
+
+// profileSource は設定ファイルから読み込んだプロファイルの設定値を保持する構造体
+type profileSource struct {
+	path    string            // 読み込んだ設定ファイル（読み込んでいない場合は空）
+	profile string            // 使用したプロファイル（使用していない場合は空）
+	values  map[string]string // 環境変数名をキーとした設定値
+}
+
+// readProfile は設定ファイルから profile の設定値を読み込む
+// profile が空の場合は current_profile、それも空の場合は default プロファイルを使用する（存在しなければ使用しない）
+// required が false の場合、設定ファイルが存在しなければ空の設定値を返す
+func readProfile(path, profile string, required bool) (*profileSource, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		if profile != "" {
+			return nil, fmt.Errorf("%w: %s (config file not found)", ErrUnknownProfile, profile)
+		}
+
+		return &profileSource{path: "", profile: "", values: map[string]string{}}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	file, err := decodeFile(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile == "" {
+		profile = file.CurrentProfile
+	}
+
+	settings, ok := file.Profiles[profile]
+
+	switch {
+	case profile == "":
+		if settings, ok = file.Profiles[defaultProfile]; !ok {
+			return &profileSource{path: path, profile: "", values: map[string]string{}}, nil
+		}
+
+		profile = defaultProfile
+	case !ok:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProfile, profile)
+	}
+
+	values, err := toEnvironment(settings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %w", profile, err)
+	}
+
+	return &profileSource{path: path, profile: profile, values: values}, nil
+}
+
+// decodeFile は拡張子に応じて YAML または TOML の設定ファイルを解析する
+func decodeFile(path string, data []byte) (*fileConfig, error) {
+	var file fileConfig
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedConfigExt, path)
+	}
+
+	return &file, nil
+}
+
+// toEnvironment はプロファイルの設定値を環境変数名と文字列の組に変換する
+func toEnvironment(settings map[string]any) (map[string]string, error) {
+	known, err := knownKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(settings))
+
+	for key, value := range settings {
+		name := strings.ToUpper(key)
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownConfigKey, key)
+		}
+
+		switch typed := value.(type) {
+		case string:
+			values[name] = typed
+		case bool:
+			values[name] = strconv.FormatBool(typed)
+		case int:
+			values[name] = strconv.Itoa(typed)
+		case int64:
+			values[name] = strconv.FormatInt(typed, 10)
+		case uint64:
+			values[name] = strconv.FormatUint(typed, 10)
+		case float64:
+			values[name] = strconv.FormatFloat(typed, 'f', -1, 64)
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrInvalidConfigValue, key)
+		}
+	}
+
+	return values, nil
+}
+
+// knownKeys は Config が読み込む環境変数名の一覧を返す
+func knownKeys() (map[string]struct{}, error) {
+	params, err := env.GetFieldParams(&Config{}) //nolint:exhaustruct // フィールドの定義のみ参照する
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect config: %w", err)
+	}
+
+	keys := make(map[string]struct{}, len(params))
+	for _, param := range params {
+		if !param.Ignored {
+			keys[param.Key] = struct{}{}
+		}
+	}
+
+	return keys, nil
+}
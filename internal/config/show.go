@@ -0,0 +1,79 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maskedValue は secret タグを付与した設定値の代わりに表示する文字列
+const maskedValue = "********"
+
+// WriteMasked は解決済みの設定を、設定ファイルのプロファイルと同じキーの YAML として書き出す
+// secret タグを付与したフィールドの値は伏せて表示する
+func (c *Config) WriteMasked(writer io.Writer) error {
+	value := reflect.ValueOf(*c)
+	mapping := node(yaml.MappingNode, "")
+	mapping.HeadComment = fmt.Sprintf("file: %s\nprofile: %s", orNone(c.File), orNone(c.Profile))
+
+	for i := range value.NumField() {
+		field := value.Type().Field(i)
+
+		key, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+
+		text := fmt.Sprint(value.Field(i).Interface())
+		if values, ok := value.Field(i).Interface().([]string); ok {
+			text = strings.Join(values, cmp.Or(field.Tag.Get("envSeparator"), ","))
+		}
+
+		if field.Tag.Get("secret") == "true" && text != "" {
+			text = maskedValue
+		}
+
+		scalar := node(yaml.ScalarNode, text)
+		if text == "" {
+			scalar.Style = yaml.DoubleQuotedStyle // null と区別するため空文字列は "" と表示する
+		}
+
+		mapping.Content = append(mapping.Content, node(yaml.ScalarNode, strings.ToLower(key)), scalar)
+	}
+
+	encoder := yaml.NewEncoder(writer)
+	encoder.SetIndent(2) //nolint:mnd // インデント幅
+
+	if err := encoder.Encode(mapping); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+
+	return nil
+}
+
+// node は YAML のノードを生成する
+func node(kind yaml.Kind, value string) *yaml.Node {
+	return &yaml.Node{Kind: kind, Value: value} //nolint:exhaustruct // 未指定のフィールドは既定値を使用する
+}
+
+// Source は設定の読み込み元の設定ファイルとプロファイルを 1 行で返す（未使用の場合は (none)）
+func (c *Config) Source() string {
+	return fmt.Sprintf("file: %s, profile: %s", orNone(c.File), orNone(c.Profile))
+}
+
+// orNone は空文字列の場合に (none) を返す
+func orNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+
+	return value
+}
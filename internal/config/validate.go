@@ -0,0 +1,251 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidConfig は、設定値が不正な場合のエラー
+var ErrInvalidConfig = errors.New("invalid config")
+
+// FieldError は 1 つの設定値の問題を表す構造体
+type FieldError struct {
+	Key    string // 環境変数名（例: REST_ENDPOINT）
+	Value  string // 設定されている値
+	Reason string // 問題と対処方法
+}
+
+// Error はエラーメッセージを返す
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s=%q: %s", e.Key, e.Value, e.Reason)
+}
+
+// ValidationError は設定値の問題をすべてまとめたエラー
+// errors.Is(err, ErrInvalidConfig) で判定できる
+type ValidationError struct {
+	Problems []FieldError
+}
+
+// Error は問題を 1 行ずつ列挙したエラーメッセージを返す
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("%s: %d problem(s)", ErrInvalidConfig, len(e.Problems)))
+
+	for _, problem := range e.Problems {
+		lines = append(lines, "  - "+problem.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap は ErrInvalidConfig を返す
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// validator は検証中に見つかった問題を蓄積する
+type validator struct {
+	problems []FieldError
+}
+
+// fail は問題を 1 件追加する
+func (v *validator) fail(key string, value any, format string, args ...any) {
+	v.problems = append(v.problems, FieldError{Key: key, Value: fmt.Sprint(value), Reason: fmt.Sprintf(format, args...)})
+}
+
+// Validate は設定値を検証し、問題があればすべてを ValidationError にまとめて返す
+func (c *Config) Validate() error {
+	v := &validator{problems: nil}
+
+	c.validateEndpoints(v)
+	c.validateQuery(v)
+	c.validateTLS(v)
+	c.validateAuth(v)
+	c.validateDelivery(v)
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+
+	return nil
+}
+
+// validateEndpoints は通信方式と接続先を検証する
+func (c *Config) validateEndpoints(v *validator) {
+	if c.Transport != "grpc" && c.Transport != "rest" {
+		v.fail("TRANSPORT", c.Transport, "must be grpc or rest")
+	}
+
+	if reason := checkGRPCTarget(c.GRPCEndpoint); reason != "" {
+		v.fail("GRPC_ENDPOINT", c.GRPCEndpoint, "%s", reason)
+	}
+
+	endpoint, err := url.Parse(c.RESTEndpoint)
+
+	switch {
+	case err != nil || endpoint.Host == "":
+		v.fail("REST_ENDPOINT", c.RESTEndpoint, "must be an absolute URL such as http://localhost:8080")
+	case endpoint.Scheme != "http" && endpoint.Scheme != "https":
+		v.fail("REST_ENDPOINT", c.RESTEndpoint, "scheme must be http or https")
+	case endpoint.RawQuery != "" || endpoint.Fragment != "":
+		v.fail("REST_ENDPOINT", c.RESTEndpoint, "must not contain a query or fragment")
+	}
+}
+
+// checkGRPCTarget は gRPC の接続先が host:port 形式（dns:/// を含む）または unix: ソケットかを検証する
+// 問題がなければ空文字列を返す
+func checkGRPCTarget(target string) string {
+	if strings.HasPrefix(target, "unix:") {
+		return ""
+	}
+
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(target, "dns:///"))
+	if err != nil {
+		return "must be in host:port form such as localhost:50051"
+	}
+
+	if host == "" {
+		return "host must not be empty"
+	}
+
+	if number, err := strconv.Atoi(port); err != nil || number < 1 || number > math.MaxUint16 {
+		return "port must be a number between 1 and 65535"
+	}
+
+	return ""
+}
+
+// validateQuery はログ取得の件数・開始位置（gRPC / REST ともに int32 で送信する）とタイムスタンプのレイアウトを検証する
+func (c *Config) validateQuery(v *validator) {
+	checkRange(v, "DEFAULT_LIMIT", c.DefaultLimit, 1, math.MaxInt32)
+	checkRange(v, "DEFAULT_OFFSET", c.DefaultOffset, 0, math.MaxInt32)
+	checkRange(v, "QUERY_PAGE_SIZE", c.QueryPageSize, 1, math.MaxInt32)
+	checkRange(v, "QUERY_MAX_RESULTS", c.QueryMaxResults, 0, math.MaxInt)
+
+	for _, layout := range c.TimestampLayouts {
+		if strings.TrimSpace(layout) == "" {
+			v.fail("TIMESTAMP_LAYOUTS", strings.Join(c.TimestampLayouts, ";"), "must not contain an empty layout")
+
+			break
+		}
+	}
+}
+
+// validateTLS は TLS の設定を検証する
+func (c *Config) validateTLS(v *validator) {
+	checkFile(v, "TLS_CA_FILE", c.TLSCAFile)
+	checkFile(v, "TLS_CERT_FILE", c.TLSCertFile)
+	checkFile(v, "TLS_KEY_FILE", c.TLSKeyFile)
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		v.fail("TLS_CERT_FILE", c.TLSCertFile, "TLS_CERT_FILE and TLS_KEY_FILE must be specified together")
+	}
+
+	if c.GRPCInsecure && c.Transport == "grpc" &&
+		(c.TLSCAFile != "" || c.TLSCertFile != "" || c.TLSKeyFile != "" || c.TLSServerName != "") {
+		v.fail("GRPC_INSECURE", c.GRPCInsecure, "cannot be combined with TLS_* settings")
+	}
+}
+
+// validateAuth は認証の設定を検証する
+func (c *Config) validateAuth(v *validator) {
+	if c.AuthType != "bearer" && c.AuthType != "api-key" {
+		v.fail("AUTH_TYPE", c.AuthType, "must be bearer or api-key")
+	}
+
+	sources := 0
+
+	for _, value := range []string{c.AuthToken, c.AuthTokenFile, c.AuthTokenCommand} {
+		if value != "" {
+			sources++
+		}
+	}
+
+	if sources > 1 {
+		v.fail("AUTH_TOKEN", maskedValue, "only one of AUTH_TOKEN, AUTH_TOKEN_FILE and AUTH_TOKEN_COMMAND can be specified")
+	}
+
+	checkFile(v, "AUTH_TOKEN_FILE", c.AuthTokenFile)
+
+	if c.AuthTokenCommand != "" {
+		checkPositive(v, "AUTH_TOKEN_TTL", c.AuthTokenTTL)
+	}
+}
+
+// validateDelivery はバッチ送信・再試行・WAL の設定を検証する
+func (c *Config) validateDelivery(v *validator) {
+	checkRange(v, "BATCH_MAX_COUNT", c.BatchMaxCount, 1, math.MaxInt)
+	checkRange(v, "BATCH_MAX_BYTES", c.BatchMaxBytes, 0, math.MaxInt)
+	checkPositive(v, "BATCH_MAX_LINGER", c.BatchMaxLinger)
+
+	checkRange(v, "RETRY_MAX_ATTEMPTS", c.RetryMaxAttempts, 0, math.MaxInt)
+
+	if c.RetryMaxAttempts > 1 {
+		checkPositive(v, "RETRY_INITIAL_BACKOFF", c.RetryInitialBackoff)
+
+		if c.RetryMaxBackoff < c.RetryInitialBackoff {
+			v.fail("RETRY_MAX_BACKOFF", c.RetryMaxBackoff, "must not be less than RETRY_INITIAL_BACKOFF (%s)", c.RetryInitialBackoff)
+		}
+
+		if c.RetryMultiplier < 1 {
+			v.fail("RETRY_MULTIPLIER", c.RetryMultiplier, "must be 1 or greater")
+		}
+
+		if c.RetryJitter < 0 || c.RetryJitter > 1 {
+			v.fail("RETRY_JITTER", c.RetryJitter, "must be between 0 and 1")
+		}
+	}
+
+	if c.WALDir != "" {
+		if c.WALMaxBytes <= 0 {
+			v.fail("WAL_MAX_BYTES", c.WALMaxBytes, "must be positive")
+		}
+
+		if c.WALSegmentBytes <= 0 || c.WALSegmentBytes > c.WALMaxBytes {
+			v.fail("WAL_SEGMENT_BYTES", c.WALSegmentBytes, "must be positive and not exceed WAL_MAX_BYTES (%d)", c.WALMaxBytes)
+		}
+
+		checkPositive(v, "WAL_RETRY_INTERVAL", c.WALRetryInterval)
+
+		if c.WALDrainTimeout < 0 {
+			v.fail("WAL_DRAIN_TIMEOUT", c.WALDrainTimeout, "must not be negative")
+		}
+	}
+}
+
+// checkRange は value が min 以上 max 以下であることを検証する
+func checkRange(v *validator, key string, value, minValue, maxValue int) {
+	if value < minValue || value > maxValue {
+		v.fail(key, value, "must be between %d and %d", minValue, maxValue)
+	}
+}
+
+// checkPositive は期間が正であることを検証する
+func checkPositive(v *validator, key string, value time.Duration) {
+	if value <= 0 {
+		v.fail(key, value, "must be a positive duration such as 1s or 500ms")
+	}
+}
+
+// checkFile は path が指定されている場合に読み取り可能なファイルであることを検証する
+func checkFile(v *validator, key, path string) {
+	if path == "" {
+		return
+	}
+
+	info, err := os.Stat(path)
+
+	switch {
+	case err != nil:
+		v.fail(key, path, "file is not accessible: %v", errors.Unwrap(err))
+	case info.IsDir():
+		v.fail(key, path, "must be a file, not a directory")
+	}
+}
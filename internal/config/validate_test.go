@@ -0,0 +1,103 @@
+package config_test
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/config"
+)
+
+// TestValidate_Defaults は既定値の設定が検証を通ることを検証する
+//
+//nolint:paralleltest // t.Setenv を使用するため並列実行しない
+func TestValidate_Defaults(t *testing.T) {
+	t.Setenv(config.ProfileEnv, "")
+	t.Setenv(config.ConfigFileEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg, err := config.Load(config.LoadOptions{Path: "", Profile: ""})
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+}
+
+// TestValidate_ReportsAllProblems は複数の問題がまとめて報告されることを検証する
+//
+//nolint:paralleltest // t.Setenv を使用するため並列実行しない
+func TestValidate_ReportsAllProblems(t *testing.T) {
+	t.Setenv(config.ProfileEnv, "")
+	t.Setenv(config.ConfigFileEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("REST_ENDPOINT", "localhost:8080")
+	t.Setenv("GRPC_ENDPOINT", "localhost")
+	t.Setenv("DEFAULT_LIMIT", "-1")
+	t.Setenv("TLS_CA_FILE", filepath.Join(t.TempDir(), "missing.pem"))
+
+	cfg, err := config.Load(config.LoadOptions{Path: "", Profile: ""})
+	require.NoError(t, err)
+
+	cfg.QueryPageSize = math.MaxInt32 + 1 // フラグで int32 を超える値が指定された場合
+
+	err = cfg.Validate()
+	require.ErrorIs(t, err, config.ErrInvalidConfig)
+
+	var validationErr *config.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+
+	keys := make([]string, 0, len(validationErr.Problems))
+	for _, problem := range validationErr.Problems {
+		keys = append(keys, problem.Key)
+	}
+
+	require.ElementsMatch(t, []string{"GRPC_ENDPOINT", "REST_ENDPOINT", "DEFAULT_LIMIT", "QUERY_PAGE_SIZE", "TLS_CA_FILE"}, keys)
+	require.Contains(t, err.Error(), `REST_ENDPOINT="localhost:8080": must be an absolute URL such as http://localhost:8080`)
+}
+
+// TestValidate_Dependencies は設定値どうしの組み合わせが検証されることを検証する
+//
+//nolint:paralleltest // t.Setenv を使用するため並列実行しない
+func TestValidate_Dependencies(t *testing.T) {
+	t.Setenv(config.ProfileEnv, "")
+	t.Setenv(config.ConfigFileEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg, err := config.Load(config.LoadOptions{Path: "", Profile: ""})
+	require.NoError(t, err)
+
+	cfg.AuthToken = "token"
+	cfg.AuthTokenCommand = "echo token"
+	cfg.RetryMaxAttempts = 3
+	cfg.RetryInitialBackoff = cfg.RetryMaxBackoff * 2
+
+	var validationErr *config.ValidationError
+	require.ErrorAs(t, cfg.Validate(), &validationErr)
+	require.Len(t, validationErr.Problems, 2)
+	require.Equal(t, "AUTH_TOKEN", validationErr.Problems[0].Key)
+	require.Equal(t, "********", validationErr.Problems[0].Value)
+	require.Equal(t, "RETRY_MAX_BACKOFF", validationErr.Problems[1].Key)
+}
+
+// TestValidate_BatchLimits は BATCH_MAX_COUNT が 1 以上、BATCH_MAX_BYTES が 0（無制限）以上であることを検証する
+// client.NewBatchingClient が受け付ける範囲と一致させる
+//
+//nolint:paralleltest // t.Setenv を使用するため並列実行しない
+func TestValidate_BatchLimits(t *testing.T) {
+	t.Setenv(config.ProfileEnv, "")
+	t.Setenv(config.ConfigFileEnv, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	cfg, err := config.Load(config.LoadOptions{Path: "", Profile: ""})
+	require.NoError(t, err)
+
+	cfg.BatchMaxBytes = 0
+	require.NoError(t, cfg.Validate())
+
+	cfg.BatchMaxCount = 0
+
+	var validationErr *config.ValidationError
+	require.ErrorAs(t, cfg.Validate(), &validationErr)
+	require.Len(t, validationErr.Problems, 1)
+	require.Equal(t, "BATCH_MAX_COUNT", validationErr.Problems[0].Key)
+}
@@ -0,0 +1,110 @@
+package container
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ログに付与するメタデータのキー
+const (
+	MetadataStream      = "stream"
+	MetadataContainerID = "container_id"
+	MetadataContainer   = "container"
+	MetadataPod         = "pod"
+	MetadataNamespace   = "namespace"
+	MetadataPodUID      = "pod_uid"
+)
+
+// コンテナ ID（16 進数）の桁数
+const (
+	containerIDLength      = 64
+	shortContainerIDLength = 12 // docker ps などで表示する短縮形
+)
+
+// PathMetadata はコンテナログのファイルパスからコンテナの情報を取り出す（該当しないパスの場合は nil）
+// 以下のパスに対応する
+//   - /var/log/containers/<pod>_<namespace>_<container>-<container_id>.log（kubelet が作成するシンボリックリンク）
+//   - /var/log/pods/<namespace>_<pod>_<pod_uid>/<container>/<n>.log
+//   - /var/lib/docker/containers/<container_id>/<container_id>-json.log
+func PathMetadata(path string) map[string]string {
+	base := filepath.Base(path)
+	dir := filepath.Dir(path)
+
+	switch {
+	case filepath.Base(dir) == "containers" && strings.HasSuffix(base, ".log"):
+		return kubeletLinkMetadata(strings.TrimSuffix(base, ".log"))
+	case filepath.Base(filepath.Dir(filepath.Dir(dir))) == "pods":
+		return podDirMetadata(filepath.Base(filepath.Dir(dir)), filepath.Base(dir))
+	case strings.HasSuffix(base, "-json.log") && isContainerID(filepath.Base(dir)):
+		return map[string]string{MetadataContainerID: filepath.Base(dir)}
+	default:
+		return nil
+	}
+}
+
+// ServiceName は PathMetadata のメタデータからサービス名に使用する名前を返す
+// コンテナ名、短縮形のコンテナ ID の順に使用し、いずれもない場合は空文字列を返す
+func ServiceName(metadata map[string]string) string {
+	if name := metadata[MetadataContainer]; name != "" {
+		return name
+	}
+
+	id := metadata[MetadataContainerID]
+	if len(id) > shortContainerIDLength {
+		return id[:shortContainerIDLength]
+	}
+
+	return id
+}
+
+// kubeletLinkMetadata は "<pod>_<namespace>_<container>-<container_id>" の形式の名前を分解する
+// Kubernetes のリソース名は '_' を含まないため、'_' で区切る
+func kubeletLinkMetadata(name string) map[string]string {
+	parts := strings.Split(name, "_")
+	if len(parts) != 3 {
+		return nil
+	}
+
+	// コンテナ名は '-' を含められるため、末尾の 64 桁をコンテナ ID とする
+	separator := len(parts[2]) - containerIDLength - 1
+	if separator <= 0 || parts[2][separator] != '-' || !isContainerID(parts[2][separator+1:]) {
+		return nil
+	}
+
+	return map[string]string{
+		MetadataPod:         parts[0],
+		MetadataNamespace:   parts[1],
+		MetadataContainer:   parts[2][:separator],
+		MetadataContainerID: parts[2][separator+1:],
+	}
+}
+
+// podDirMetadata は "<namespace>_<pod>_<pod_uid>" のディレクトリ名とコンテナ名を分解する
+func podDirMetadata(podDir, container string) map[string]string {
+	parts := strings.Split(podDir, "_")
+	if len(parts) != 3 {
+		return nil
+	}
+
+	return map[string]string{
+		MetadataNamespace: parts[0],
+		MetadataPod:       parts[1],
+		MetadataPodUID:    parts[2],
+		MetadataContainer: container,
+	}
+}
+
+// isContainerID は 64 桁の 16 進数かを返す
+func isContainerID(value string) bool {
+	if len(value) != containerIDLength {
+		return false
+	}
+
+	for _, char := range value {
+		if !strings.ContainsRune("0123456789abcdef", char) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,55 @@
+package container_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/container"
+)
+
+// TestPathMetadata はコンテナログのファイルパスからコンテナの情報を取り出すことを検証する
+func TestPathMetadata(t *testing.T) {
+	t.Parallel()
+
+	id := strings.Repeat("0123456789abcdef", 4)
+
+	tests := []struct {
+		name    string
+		path    string
+		want    map[string]string
+		service string
+	}{
+		{
+			"kubelet symlink",
+			"/var/log/containers/web-7f9c_prod_api-server-" + id + ".log",
+			map[string]string{"pod": "web-7f9c", "namespace": "prod", "container": "api-server", "container_id": id},
+			"api-server",
+		},
+		{
+			"pod directory",
+			"/var/log/pods/prod_web-7f9c_6f1c-4e2a/api-server/3.log",
+			map[string]string{"namespace": "prod", "pod": "web-7f9c", "pod_uid": "6f1c-4e2a", "container": "api-server"},
+			"api-server",
+		},
+		{
+			"docker json-file",
+			"/var/lib/docker/containers/" + id + "/" + id + "-json.log",
+			map[string]string{"container_id": id},
+			"0123456789ab",
+		},
+		{"unrelated file", "/var/log/app/app.log", nil, ""},
+		{"containers directory without id", "/var/log/containers/app.log", nil, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			metadata := container.PathMetadata(test.path)
+			require.Equal(t, test.want, metadata)
+			require.Equal(t, test.service, container.ServiceName(metadata))
+		})
+	}
+}
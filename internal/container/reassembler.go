@@ -0,0 +1,92 @@
+package container
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultMaxLineBytes は連結する 1 行の既定の最大バイト数
+const DefaultMaxLineBytes = 1024 * 1024
+
+// pendingLine は連結中の行の先頭の記録とその位置、それまでの出力
+type pendingLine struct {
+	first   Record
+	offset  int64
+	message strings.Builder
+}
+
+// Reassembler は分割された記録（Record.Partial）を入力元とストリームごとに 1 行へ連結する
+//
+// 連結した行を送信できなかった場合に最後の記録から再度渡せるよう、連結した行は Release を呼び出すまで保持する
+type Reassembler struct {
+	maxBytes int
+
+	mutex   sync.Mutex
+	pending map[string]*pendingLine
+}
+
+// NewReassembler は Reassembler を生成する（maxBytes が 0 以下の場合は DefaultMaxLineBytes）
+func NewReassembler(maxBytes int) *Reassembler {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxLineBytes
+	}
+
+	return &Reassembler{maxBytes: maxBytes, mutex: sync.Mutex{}, pending: map[string]*pendingLine{}}
+}
+
+// Join は入力元 source の offset（ファイル内の位置など）にある記録を連結中の行に追加する
+// 行が完成した場合（最後の記録、または連結した出力が最大バイト数に達した場合）は、先頭の記録の時刻で連結した記録と、
+// 先頭の記録の位置、true を返す
+// 完成した行は Release を呼び出すまで保持し、Release の前に同じ記録が再度渡された場合は同じ行を返す
+func (r *Reassembler) Join(source string, record Record, offset int64) (Record, int64, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := source + "\x00" + record.Stream
+
+	line, ok := r.pending[key]
+	if !ok {
+		line = &pendingLine{first: record, offset: offset, message: strings.Builder{}}
+		r.pending[key] = line
+	}
+
+	if !record.Partial || line.message.Len()+len(record.Message) >= r.maxBytes {
+		joined := line.first
+		joined.Partial = false
+		joined.Message = line.message.String() + record.Message
+
+		return joined, line.offset, true
+	}
+
+	line.message.WriteString(record.Message)
+
+	return Record{}, 0, false
+}
+
+// Pending は入力元 source で連結中（Release 前の完成した行を含む）の行のうち、最も前にある先頭の記録の位置を返す
+// 連結中の行がない場合は false を返す
+func (r *Reassembler) Pending(source string) (int64, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var (
+		oldest int64
+		found  bool
+	)
+
+	for _, stream := range []string{StreamStdout, StreamStderr} {
+		if line, ok := r.pending[source+"\x00"+stream]; ok && (!found || line.offset < oldest) {
+			oldest, found = line.offset, true
+		}
+	}
+
+	return oldest, found
+}
+
+// Release は Join で完成した行の送信が終わった後に、入力元とストリームの連結中の行を破棄する
+func (r *Reassembler) Release(source, stream string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.pending, source+"\x00"+stream)
+}
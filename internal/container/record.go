@@ -0,0 +1,141 @@
+// Package container は、Docker の json-file 形式と Kubernetes の CRI 形式のコンテナログを解析する
+// 1 行の出力が分割された記録の連結と、ファイルパスからのコンテナ情報の取り出しも提供する
+package container
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ログの形式（Parse に指定する値）
+const (
+	FormatAuto   = "auto"
+	FormatDocker = "docker"
+	FormatCRI    = "cri"
+)
+
+// 出力先のストリーム
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+)
+
+// CRI 形式のタグ（P は行の途中で分割された記録、F は行の最後の記録）
+const (
+	criTagPartial = "P"
+	criTagFull    = "F"
+)
+
+// 解析に関するエラー
+var (
+	ErrUnknownFormat = errors.New("unknown container log format")
+	ErrInvalidRecord = errors.New("invalid container log record")
+)
+
+// Record はコンテナログの 1 件の記録
+type Record struct {
+	Timestamp time.Time // コンテナランタイムが記録した時刻
+	Stream    string    // 出力先のストリーム（StreamStdout / StreamStderr）
+	Partial   bool      // 行の途中で分割された記録の場合は true（続きの記録と連結する）
+	Message   string    // 末尾の改行を除いた出力
+}
+
+// dockerRecord は Docker の json-file 形式の 1 行
+type dockerRecord struct {
+	Log    string `json:"log"`
+	Stream string `json:"stream"`
+	Time   string `json:"time"`
+}
+
+// ValidateFormat は形式の名前が対応しているものかを検証する
+func ValidateFormat(format string) error {
+	switch format {
+	case FormatAuto, FormatDocker, FormatCRI:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q (must be %s, %s or %s)", ErrUnknownFormat, format, FormatAuto, FormatDocker, FormatCRI)
+	}
+}
+
+// Parse は 1 行を format の形式の記録として解析する
+// FormatAuto の場合は '{' で始まる行を Docker、それ以外を CRI の形式とみなす
+func Parse(format, line string) (Record, error) {
+	switch format {
+	case FormatDocker:
+		return parseDocker(line)
+	case FormatCRI:
+		return parseCRI(line)
+	case FormatAuto:
+		if strings.HasPrefix(line, "{") {
+			return parseDocker(line)
+		}
+
+		return parseCRI(line)
+	default:
+		return Record{}, ValidateFormat(format)
+	}
+}
+
+// parseDocker は Docker の json-file 形式（{"log":"...\n","stream":"stdout","time":"..."}）の行を解析する
+// Docker は 16 KiB を超える行を分割し、最後の記録以外は log を改行で終えない
+func parseDocker(line string) (Record, error) {
+	var raw dockerRecord
+	if err := json.Unmarshal([]byte(line), &raw); err != nil {
+		return Record{}, fmt.Errorf("%w: %w", ErrInvalidRecord, err)
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, raw.Time)
+	if err != nil {
+		return Record{}, fmt.Errorf("%w: invalid time %q", ErrInvalidRecord, raw.Time)
+	}
+
+	message, full := strings.CutSuffix(raw.Log, "\n")
+	if full {
+		message = strings.TrimSuffix(message, "\r")
+	}
+
+	return Record{
+		Timestamp: timestamp,
+		Stream:    raw.Stream,
+		Partial:   !full,
+		Message:   message,
+	}, nil
+}
+
+// parseCRI は CRI 形式（"時刻 ストリーム タグ 出力"）の行を解析する
+// タグは ':' 区切りの複数のフラグを含められるため、先頭のフラグのみを判定する
+func parseCRI(line string) (Record, error) {
+	value, rest, ok := strings.Cut(line, " ")
+	stream, rest, found := strings.Cut(rest, " ")
+
+	if !ok || !found {
+		return Record{}, fmt.Errorf("%w: expected \"<time> <stream> <tag> <log>\"", ErrInvalidRecord)
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return Record{}, fmt.Errorf("%w: invalid time %q", ErrInvalidRecord, value)
+	}
+
+	if stream != StreamStdout && stream != StreamStderr {
+		return Record{}, fmt.Errorf("%w: invalid stream %q", ErrInvalidRecord, stream)
+	}
+
+	// 出力が空の場合は "時刻 ストリーム タグ" で終わる
+	tags, message, _ := strings.Cut(rest, " ")
+
+	tag, _, _ := strings.Cut(tags, ":")
+	if tag != criTagPartial && tag != criTagFull {
+		return Record{}, fmt.Errorf("%w: invalid tag %q", ErrInvalidRecord, tags)
+	}
+
+	return Record{
+		Timestamp: timestamp,
+		Stream:    stream,
+		Partial:   tag == criTagPartial,
+		Message:   message,
+	}, nil
+}
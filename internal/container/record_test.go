@@ -0,0 +1,166 @@
+package container_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/container"
+)
+
+// TestParse は Docker と CRI の形式の行が記録に変換されることを検証する
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format string
+		line   string
+		want   container.Record
+	}{
+		{
+			"docker",
+			container.FormatDocker,
+			`{"log":"GET /health 200\r\n","stream":"stdout","time":"2025-01-02T03:04:05.123456789Z"}`,
+			container.Record{Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC), Stream: "stdout", Partial: false, Message: "GET /health 200"},
+		},
+		{
+			"docker partial",
+			container.FormatDocker,
+			`{"log":"first half ","stream":"stderr","time":"2025-01-02T03:04:05Z"}`,
+			container.Record{Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Stream: "stderr", Partial: true, Message: "first half "},
+		},
+		{
+			"cri",
+			container.FormatCRI,
+			"2025-01-02T03:04:05.5+09:00 stderr F panic: boom",
+			container.Record{Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 500000000, time.FixedZone("", 9*60*60)), Stream: "stderr", Partial: false, Message: "panic: boom"},
+		},
+		{
+			"cri partial with extra flags",
+			container.FormatCRI,
+			"2025-01-02T03:04:05Z stdout P:x  leading spaces kept",
+			container.Record{Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Stream: "stdout", Partial: true, Message: " leading spaces kept"},
+		},
+		{
+			"cri empty line",
+			container.FormatCRI,
+			"2025-01-02T03:04:05Z stdout F",
+			container.Record{Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Stream: "stdout", Partial: false, Message: ""},
+		},
+		{
+			"auto detects docker",
+			container.FormatAuto,
+			`{"log":"hello\n","stream":"stdout","time":"2025-01-02T03:04:05Z"}`,
+			container.Record{Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Stream: "stdout", Partial: false, Message: "hello"},
+		},
+		{
+			"auto detects cri",
+			container.FormatAuto,
+			"2025-01-02T03:04:05Z stdout F hello",
+			container.Record{Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Stream: "stdout", Partial: false, Message: "hello"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			record, err := container.Parse(test.format, test.line)
+			require.NoError(t, err)
+			require.True(t, test.want.Timestamp.Equal(record.Timestamp), record.Timestamp)
+
+			record.Timestamp = test.want.Timestamp
+			require.Equal(t, test.want, record)
+		})
+	}
+}
+
+// TestParse_Invalid は形式に合わない行や未対応の形式がエラーになることを検証する
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, line := range []string{
+		"plain text",
+		`{"log":"x\n","stream":"stdout","time":"yesterday"}`,
+		"2025-01-02T03:04:05Z stdout",
+		"2025-01-02T03:04:05Z console F hello",
+		"2025-01-02T03:04:05Z stdout X hello",
+		"yesterday stdout F hello",
+	} {
+		_, err := container.Parse(container.FormatAuto, line)
+		require.ErrorIs(t, err, container.ErrInvalidRecord, line)
+	}
+
+	_, err := container.Parse("podman", "")
+	require.ErrorIs(t, err, container.ErrUnknownFormat)
+	require.ErrorIs(t, container.ValidateFormat("podman"), container.ErrUnknownFormat)
+}
+
+// TestReassembler は分割された記録が入力元とストリームごとに連結され、Release まで保持されることを検証する
+func TestReassembler(t *testing.T) {
+	t.Parallel()
+
+	first := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	reassembler := container.NewReassembler(0)
+
+	_, _, complete := reassembler.Join("a.log", container.Record{Timestamp: first, Stream: "stdout", Partial: true, Message: "hel"}, 10)
+	require.False(t, complete)
+
+	// 別のストリーム・入力元の記録は連結しない
+	joined, start, complete := reassembler.Join("a.log", container.Record{Timestamp: first.Add(time.Second), Stream: "stderr", Partial: false, Message: "error"}, 20)
+	require.True(t, complete)
+	require.Equal(t, "error", joined.Message)
+	require.Equal(t, int64(20), start)
+	reassembler.Release("a.log", "stderr")
+
+	_, _, complete = reassembler.Join("b.log", container.Record{Timestamp: first, Stream: "stdout", Partial: true, Message: "other"}, 0)
+	require.False(t, complete)
+
+	// 連結中の行の先頭の記録の位置を返す
+	offset, pending := reassembler.Pending("a.log")
+	require.True(t, pending)
+	require.Equal(t, int64(10), offset)
+
+	last := container.Record{Timestamp: first.Add(2 * time.Second), Stream: "stdout", Partial: false, Message: "lo"}
+
+	joined, start, complete = reassembler.Join("a.log", last, 30)
+	require.True(t, complete)
+	require.Equal(t, container.Record{Timestamp: first, Stream: "stdout", Partial: false, Message: "hello"}, joined)
+	require.Equal(t, int64(10), start)
+
+	// 送信に失敗して最後の記録が再度渡された場合も同じ行になる
+	joined, _, complete = reassembler.Join("a.log", last, 30)
+	require.True(t, complete)
+	require.Equal(t, "hello", joined.Message)
+
+	reassembler.Release("a.log", "stdout")
+
+	_, pending = reassembler.Pending("a.log")
+	require.False(t, pending)
+
+	joined, start, complete = reassembler.Join("a.log", last, 30)
+	require.True(t, complete)
+	require.Equal(t, "lo", joined.Message)
+	require.Equal(t, int64(30), start)
+}
+
+// TestReassembler_MaxBytes は最大バイト数に達した時点で連結中の行を完成させることを検証する
+func TestReassembler_MaxBytes(t *testing.T) {
+	t.Parallel()
+
+	reassembler := container.NewReassembler(8)
+
+	var lines []string
+
+	for _, part := range []string{"abc", "def", "ghi", "jk"} {
+		joined, _, complete := reassembler.Join("a.log", container.Record{Timestamp: time.Time{}, Stream: "stdout", Partial: true, Message: part}, 0)
+		if complete {
+			lines = append(lines, joined.Message)
+			reassembler.Release("a.log", "stdout")
+		}
+	}
+
+	require.Equal(t, []string{"abcdefghi"}, lines)
+}
@@ -0,0 +1,204 @@
+package ingest
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	"github.com/KeitaShimura/logs-collector-client/internal/multiline"
+	"github.com/KeitaShimura/logs-collector-client/internal/parser"
+)
+
+// maxLineBytes は 1 行として読み取れる最大バイト数
+const maxLineBytes = 1024 * 1024
+
+// Sender はログを 1 件ずつ送信する送信先のインターフェース
+type Sender interface {
+	SendLog(ctx context.Context, log *model.Log) error
+}
+
+// Template は行から生成するログに設定する既定値を保持する構造体
+type Template struct {
+	Service          string
+	Level            model.Level
+	TraceID          string
+	Metadata         map[string]string
+	TimestampLayouts []string        // 行の timestamp の解釈に使用するレイアウト（空の場合は model.DefaultTimestampLayouts）
+	Parser           parser.Parser   // 行の解析に使用するパーサー（nil の場合は JSON の model.Log またはプレーンテキストとして扱う）
+	Mapping          parser.Mapping  // Parser で解析したフィールドと model.Log の項目の対応
+	Multiline        *multiline.Rule // 複数行を 1 件のログにまとめる規則（nil の場合は 1 行を 1 件とする）
+}
+
+// Stats は送信結果の件数を保持する構造体
+type Stats struct {
+	Sent   int
+	Failed int
+}
+
+// FailureHandler は送信に失敗したログとエラーを受け取るコールバック
+type FailureHandler func(log *model.Log, err error)
+
+// LineToLog は 1 行を model.Log に変換する
+// Template.Parser が指定されている場合は行を解析し、Template.Mapping に従って各項目に振り分ける
+// 指定されていない場合、行が JSON 形式の model.Log であればそれを復元し、不足している項目のみ既定値で補う
+// timestamp を解釈できない JSON の行はプレーンテキストとして扱う
+func LineToLog(line string, tmpl *Template, now time.Time) *model.Log {
+	var log *model.Log
+	if tmpl.Parser != nil {
+		log = parseLine(line, tmpl)
+	} else {
+		log = decodeJSONLog(line, tmpl.TimestampLayouts)
+	}
+
+	if log == nil {
+		log = &model.Log{
+			ID:        "",
+			TraceID:   "",
+			Timestamp: time.Time{},
+			Level:     "",
+			Service:   "",
+			Message:   line,
+			Metadata:  nil,
+		}
+	}
+
+	fillDefaults(log, tmpl, now)
+
+	return log
+}
+
+// parseLine は Template.Parser で行を解析して model.Log に変換する
+// 解析できない行は行全体をメッセージとし、メタデータの parser.ParseErrorKey に理由を設定する
+func parseLine(line string, tmpl *Template) *model.Log {
+	fields, err := tmpl.Parser.Parse(line)
+	if err != nil {
+		return &model.Log{
+			ID:        "",
+			TraceID:   "",
+			Timestamp: time.Time{},
+			Level:     "",
+			Service:   "",
+			Message:   line,
+			Metadata:  map[string]string{parser.ParseErrorKey: err.Error()},
+		}
+	}
+
+	return tmpl.Mapping.ToLog(fields, line, tmpl.TimestampLayouts)
+}
+
+// decodeJSONLog は行を JSON の model.Log として解釈する（timestamp は layouts で解釈する）
+// 解釈できない場合は nil を返す
+func decodeJSONLog(line string, layouts []string) *model.Log {
+	if !strings.HasPrefix(strings.TrimSpace(line), "{") {
+		return nil
+	}
+
+	log, err := model.DecodeLog([]byte(line), layouts)
+	if err != nil || log.Message == "" {
+		return nil
+	}
+
+	return log
+}
+
+// fillDefaults は未設定の項目を Template で補完し、残りを model.Log.Normalize で補完・正規化する
+func fillDefaults(log *model.Log, tmpl *Template, now time.Time) {
+	if log.TraceID == "" {
+		log.TraceID = tmpl.TraceID
+	}
+
+	if log.Level == "" {
+		log.Level = tmpl.Level
+	}
+
+	if log.Service == "" {
+		log.Service = tmpl.Service
+	}
+
+	// Template のメタデータは行側の値を上書きしない
+	if len(tmpl.Metadata) > 0 {
+		metadata := maps.Clone(tmpl.Metadata)
+		maps.Copy(metadata, log.Metadata)
+		log.Metadata = metadata
+	}
+
+	log.Normalize(now)
+}
+
+// Ship は reader から改行区切りで読み取った各行をログとして送信する
+// 空行は読み飛ばし、送信に失敗した行は onFailure に通知して処理を継続する
+// Template.Multiline が指定されている場合は、規則に従ってまとめた複数行を 1 件のログとして送信する
+func Ship(
+	ctx context.Context,
+	reader io.Reader,
+	sender Sender,
+	tmpl *Template,
+	onFailure FailureHandler,
+) (Stats, error) {
+	var stats Stats
+
+	ship := func(ctx context.Context, event string) error {
+		log := LineToLog(event, tmpl, time.Now())
+
+		if err := sender.SendLog(ctx, log); err != nil {
+			stats.Failed++
+
+			if onFailure != nil {
+				onFailure(log, err)
+			}
+
+			return nil
+		}
+
+		stats.Sent++
+
+		return nil
+	}
+
+	// 複数行をまとめる場合、stats は Aggregator のロックの下で更新される
+	var aggregator *multiline.Aggregator
+	if tmpl.Multiline != nil {
+		aggregator = multiline.NewAggregator(*tmpl.Multiline, ship)
+		ship = aggregator.Add
+	}
+
+	err := scanLines(ctx, reader, ship)
+
+	if aggregator != nil {
+		_ = aggregator.Close(ctx) // ship は常に nil を返す
+	}
+
+	return stats, err
+}
+
+// scanLines は reader から読み取った空行以外の各行を handle に渡す
+func scanLines(ctx context.Context, reader io.Reader, handle func(ctx context.Context, line string) error) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineBytes)
+
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("shipping interrupted: %w", err)
+		}
+
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if err := handle(ctx, line); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package ingest_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/ingest"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	"github.com/KeitaShimura/logs-collector-client/internal/multiline"
+	"github.com/KeitaShimura/logs-collector-client/internal/parser"
+)
+
+// 共通エラー定義
+var errSendFailed = errors.New("send failed")
+
+// fakeSender は送信されたログを記録するテスト用の Sender
+type fakeSender struct {
+	mutex  sync.Mutex
+	logs   []*model.Log
+	failOn string
+}
+
+// SendLog は failOn と同じメッセージの場合に失敗し、それ以外は記録する
+func (s *fakeSender) SendLog(_ context.Context, log *model.Log) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if log.Message == s.failOn {
+		return errSendFailed
+	}
+
+	s.logs = append(s.logs, log)
+
+	return nil
+}
+
+// TestLineToLog_PlainText はプレーンテキストの行が既定値付きのログに変換されることを検証する
+func TestLineToLog_PlainText(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	tmpl := &ingest.Template{Service: "billing", Level: "INFO", TraceID: "", Metadata: map[string]string{"env": "dev"}, TimestampLayouts: nil, Parser: nil, Mapping: parser.Mapping{}, Multiline: nil}
+
+	log := ingest.LineToLog("payment accepted", tmpl, now)
+
+	require.NotEmpty(t, log.ID)
+	require.NotEmpty(t, log.TraceID)
+	require.Equal(t, now, log.Timestamp)
+	require.Equal(t, "billing", log.Service)
+	require.Equal(t, model.LevelInfo, log.Level)
+	require.Equal(t, "payment accepted", log.Message)
+	require.Equal(t, map[string]string{"env": "dev"}, log.Metadata)
+}
+
+// TestLineToLog_JSON は JSON 形式の行がそのまま復元され、不足項目のみ補完されることを検証する
+func TestLineToLog_JSON(t *testing.T) {
+	t.Parallel()
+
+	tmpl := &ingest.Template{Service: "billing", Level: "INFO", TraceID: "", Metadata: map[string]string{"env": "dev"}, TimestampLayouts: nil, Parser: nil, Mapping: parser.Mapping{}, Multiline: nil}
+	line := `{"id":"abc","level":"ERROR","service":"auth","message":"denied","metadata":{"env":"prod"}}`
+
+	log := ingest.LineToLog(line, tmpl, time.Now())
+
+	require.Equal(t, "abc", log.ID)
+	require.NotEmpty(t, log.TraceID)
+	require.Equal(t, "auth", log.Service)
+	require.Equal(t, model.LevelError, log.Level)
+	require.Equal(t, "denied", log.Message)
+	require.Equal(t, "prod", log.Metadata["env"])
+}
+
+// TestLineToLog_TimestampLayouts は JSON の行の timestamp を Template のレイアウトで解釈することを検証する
+func TestLineToLog_TimestampLayouts(t *testing.T) {
+	t.Parallel()
+
+	tmpl := &ingest.Template{Service: "billing", Level: "INFO", TraceID: "", Metadata: nil, TimestampLayouts: []string{"02/01/2006 15:04"}, Parser: nil, Mapping: parser.Mapping{}, Multiline: nil}
+	line := `{"timestamp":"02/01/2025 03:04","level":"warning","message":"slow"}`
+
+	log := ingest.LineToLog(line, tmpl, time.Now())
+
+	require.Equal(t, time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC), log.Timestamp)
+	require.Equal(t, model.LevelWarn, log.Level)
+	require.Equal(t, "slow", log.Message)
+
+	// timestamp を解釈できない行はプレーンテキストとして扱う
+	log = ingest.LineToLog(`{"timestamp":"yesterday","message":"slow"}`, tmpl, time.Now())
+	require.Equal(t, `{"timestamp":"yesterday","message":"slow"}`, log.Message)
+}
+
+// TestLineToLog_Parser は Template.Parser で解析した行が Mapping に従って振り分けられることを検証する
+func TestLineToLog_Parser(t *testing.T) {
+	t.Parallel()
+
+	mapping := parser.Mapping{Level: nil, Message: nil, Timestamp: nil, TraceID: nil, Service: []string{"component"}}
+	tmpl := &ingest.Template{Service: "billing", Level: "INFO", TraceID: "", Metadata: map[string]string{"env": "dev"}, TimestampLayouts: nil, Parser: &parser.LogfmtParser{}, Mapping: mapping, Multiline: nil}
+
+	log := ingest.LineToLog(`ts=2025-01-02T03:04:05Z level=warn component=auth msg="slow login" user=alice`, tmpl, time.Now())
+
+	require.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), log.Timestamp)
+	require.Equal(t, model.LevelWarn, log.Level)
+	require.Equal(t, "auth", log.Service)
+	require.Equal(t, "slow login", log.Message)
+	require.Equal(t, map[string]string{"env": "dev", "user": "alice"}, log.Metadata)
+
+	// 解析できない行はそのままのメッセージで、理由をメタデータに付与して送信する
+	log = ingest.LineToLog(`broken "line`, tmpl, time.Now())
+
+	require.Equal(t, `broken "line`, log.Message)
+	require.Equal(t, "billing", log.Service)
+	require.Equal(t, model.LevelInfo, log.Level)
+	require.Contains(t, log.Metadata[parser.ParseErrorKey], "invalid logfmt")
+	require.Equal(t, "dev", log.Metadata["env"])
+	require.NoError(t, log.Validate())
+}
+
+// TestShip_CountsSentAndFailed は送信成功・失敗件数が集計され、空行が無視されることを検証する
+func TestShip_CountsSentAndFailed(t *testing.T) {
+	t.Parallel()
+
+	sender := &fakeSender{mutex: sync.Mutex{}, logs: nil, failOn: "bad"}
+	tmpl := &ingest.Template{Service: "billing", Level: "INFO", TraceID: "", Metadata: nil, TimestampLayouts: nil, Parser: nil, Mapping: parser.Mapping{}, Multiline: nil}
+	input := strings.NewReader("first\n\nbad\r\nsecond\n")
+
+	var failures []string
+
+	stats, err := ingest.Ship(context.Background(), input, sender, tmpl, func(log *model.Log, _ error) {
+		failures = append(failures, log.Message)
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, ingest.Stats{Sent: 2, Failed: 1}, stats)
+	require.Equal(t, []string{"bad"}, failures)
+	require.Len(t, sender.logs, 2)
+	require.Equal(t, "second", sender.logs[1].Message)
+}
+
+// TestShip_Multiline は Template.Multiline の規則で複数行が 1 件のログにまとめられることを検証する
+func TestShip_Multiline(t *testing.T) {
+	t.Parallel()
+
+	rule, err := multiline.Preset(multiline.PresetJava)
+	require.NoError(t, err)
+
+	sender := &fakeSender{mutex: sync.Mutex{}, logs: nil, failOn: ""}
+	tmpl := &ingest.Template{Service: "billing", Level: "ERROR", TraceID: "", Metadata: nil, TimestampLayouts: nil, Parser: nil, Mapping: parser.Mapping{}, Multiline: rule}
+	input := strings.NewReader("payment failed\njava.lang.IllegalStateException: declined\n\tat com.example.Billing.charge(Billing.java:42)\n\nretrying\n")
+
+	stats, err := ingest.Ship(context.Background(), input, sender, tmpl, nil)
+
+	require.NoError(t, err)
+	require.Equal(t, ingest.Stats{Sent: 2, Failed: 0}, stats)
+	require.Equal(t, "payment failed\njava.lang.IllegalStateException: declined\n\tat com.example.Billing.charge(Billing.java:42)", sender.logs[0].Message)
+	require.Equal(t, "retrying", sender.logs[1].Message)
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Level はログレベルを表す型（JSON では文字列として表現する）
+type Level string
+
+// ログレベル（Normalize 後の Level は必ずこのいずれかになる）
+const (
+	LevelTrace Level = "TRACE"
+	LevelDebug Level = "DEBUG"
+	LevelInfo  Level = "INFO"
+	LevelWarn  Level = "WARN"
+	LevelError Level = "ERROR"
+	LevelFatal Level = "FATAL"
+)
+
+// ErrUnknownLevel は、未対応のログレベルが指定された場合のエラー
+var ErrUnknownLevel = errors.New("unknown level")
+
+// levelAliases は大文字に変換したレベル表記と正規のレベルの対応表
+var levelAliases = map[string]Level{
+	string(LevelTrace): LevelTrace,
+	"TRC":              LevelTrace,
+	string(LevelDebug): LevelDebug,
+	"DBG":              LevelDebug,
+	string(LevelInfo):  LevelInfo,
+	"INF":              LevelInfo,
+	"INFORMATION":      LevelInfo,
+	"INFORMATIONAL":    LevelInfo,
+	"NOTICE":           LevelInfo,
+	string(LevelWarn):  LevelWarn,
+	"WRN":              LevelWarn,
+	"WARNING":          LevelWarn,
+	string(LevelError): LevelError,
+	"ERR":              LevelError,
+	string(LevelFatal): LevelFatal,
+	"FTL":              LevelFatal,
+	"CRIT":             LevelFatal,
+	"CRITICAL":         LevelFatal,
+	"PANIC":            LevelFatal,
+	"EMERG":            LevelFatal,
+	"EMERGENCY":        LevelFatal,
+	"ALERT":            LevelFatal,
+}
+
+// ParseLevel はレベル表記（大文字・小文字、WARNING などの別名を含む）を正規のレベルに変換する
+func ParseLevel(level string) (Level, error) {
+	canonical, ok := levelAliases[strings.ToUpper(strings.TrimSpace(level))]
+	if !ok {
+		return "", fmt.Errorf("%w: %q (must be one of TRACE, DEBUG, INFO, WARN, ERROR, FATAL)", ErrUnknownLevel, level)
+	}
+
+	return canonical, nil
+}
+
+// String はレベルを文字列として返す
+func (l Level) String() string {
+	return string(l)
+}
@@ -0,0 +1,34 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// TestParseLevel は大文字・小文字や別名が正規のレベルに変換されることを検証する
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]model.Level{
+		"trace":    model.LevelTrace,
+		"Debug":    model.LevelDebug,
+		" info ":   model.LevelInfo,
+		"WARNING":  model.LevelWarn,
+		"warn":     model.LevelWarn,
+		"err":      model.LevelError,
+		"critical": model.LevelFatal,
+		"FATAL":    model.LevelFatal,
+	}
+
+	for input, expected := range cases {
+		level, err := model.ParseLevel(input)
+		require.NoError(t, err, input)
+		require.Equal(t, expected, level, input)
+	}
+
+	_, err := model.ParseLevel("WARNNING")
+	require.ErrorIs(t, err, model.ErrUnknownLevel)
+}
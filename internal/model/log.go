@@ -1,12 +1,131 @@
 package model
 
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"time"
+)
+
+// InvalidTimestampKey は、DecodeLogLenient で解釈できなかった timestamp の元の値を保持する Metadata のキー
+const InvalidTimestampKey = "invalid_timestamp"
+
 // Log はログデータを表す構造体
+// JSON では timestamp を TimestampLayout の文字列（ゼロ値は空文字列）、level を文字列として表現する
 type Log struct {
+	ID        string
+	TraceID   string
+	Timestamp time.Time
+	Level     Level
+	Service   string
+	Message   string
+	Metadata  map[string]string
+}
+
+// wireLog は Log の JSON 上の表現
+type wireLog struct {
 	ID        string            `json:"id"`
 	TraceID   string            `json:"traceId"`
 	Timestamp string            `json:"timestamp"`
-	Level     string            `json:"level"`
+	Level     Level             `json:"level"`
 	Service   string            `json:"service"`
 	Message   string            `json:"message"`
 	Metadata  map[string]string `json:"metadata"`
 }
+
+// MarshalJSON は Log を JSON に変換する
+func (l Log) MarshalJSON() ([]byte, error) {
+	encoded, err := json.Marshal(wireLog{
+		ID:        l.ID,
+		TraceID:   l.TraceID,
+		Timestamp: FormatTimestamp(l.Timestamp),
+		Level:     l.Level,
+		Service:   l.Service,
+		Message:   l.Message,
+		Metadata:  l.Metadata,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal log: %w", err)
+	}
+
+	return encoded, nil
+}
+
+// UnmarshalJSON は JSON を Log に変換する
+// timestamp は DefaultTimestampLayouts で解釈する
+func (l *Log) UnmarshalJSON(data []byte) error {
+	return l.decode(data, nil)
+}
+
+// DecodeLog は JSON を Log に変換する。timestamp は layouts（空の場合は DefaultTimestampLayouts）で解釈する
+func DecodeLog(data []byte, layouts []string) (*Log, error) {
+	var log Log
+	if err := log.decode(data, layouts); err != nil {
+		return nil, err
+	}
+
+	return &log, nil
+}
+
+// DecodeLogLenient は JSON を Log に変換する（timestamp は DefaultTimestampLayouts で解釈する）
+// サーバーから受け取ったログなど、timestamp を解釈できない場合もエラーにせずゼロ値とし、元の値を Metadata の InvalidTimestampKey に残す
+func DecodeLogLenient(data []byte) (*Log, error) {
+	var wire wireLog
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal log: %w", err)
+	}
+
+	log, err := wire.toLog(nil)
+	if err != nil {
+		log.Metadata = maps.Clone(log.Metadata)
+		if log.Metadata == nil {
+			log.Metadata = map[string]string{}
+		}
+
+		log.Metadata[InvalidTimestampKey] = wire.Timestamp
+	}
+
+	return &log, nil
+}
+
+// decode は JSON を解析し、timestamp を layouts で解釈して Log に設定する
+func (l *Log) decode(data []byte, layouts []string) error {
+	var wire wireLog
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return fmt.Errorf("failed to unmarshal log: %w", err)
+	}
+
+	log, err := wire.toLog(layouts)
+	if err != nil {
+		return err
+	}
+
+	*l = log
+
+	return nil
+}
+
+// toLog は timestamp を layouts で解釈して Log に変換する
+// timestamp を解釈できない場合は、timestamp をゼロ値とした Log とエラーを返す
+func (w *wireLog) toLog(layouts []string) (Log, error) {
+	log := Log{
+		ID:        w.ID,
+		TraceID:   w.TraceID,
+		Timestamp: time.Time{},
+		Level:     w.Level,
+		Service:   w.Service,
+		Message:   w.Message,
+		Metadata:  w.Metadata,
+	}
+
+	if w.Timestamp != "" {
+		parsed, err := ParseTimestamp(w.Timestamp, layouts)
+		if err != nil {
+			return log, err
+		}
+
+		log.Timestamp = parsed
+	}
+
+	return log, nil
+}
@@ -0,0 +1,92 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// TestLog_MarshalJSON は timestamp / level が従来どおり文字列として出力されることを検証する
+func TestLog_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	log := model.Log{
+		ID:        "id",
+		TraceID:   "trace",
+		Timestamp: time.Date(2025, 1, 2, 12, 4, 5, 123456789, time.FixedZone("JST", 9*60*60)),
+		Level:     model.LevelWarn,
+		Service:   "billing",
+		Message:   "paid",
+		Metadata:  map[string]string{"env": "dev"},
+	}
+
+	encoded, err := json.Marshal(&log)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"id": "id",
+		"traceId": "trace",
+		"timestamp": "2025-01-02T03:04:05.123456789Z",
+		"level": "WARN",
+		"service": "billing",
+		"message": "paid",
+		"metadata": {"env": "dev"}
+	}`, string(encoded))
+
+	// ゼロ値の timestamp は空文字列とする
+	encoded, err = json.Marshal(model.Log{ID: "", TraceID: "", Timestamp: time.Time{}, Level: "", Service: "", Message: "", Metadata: nil})
+	require.NoError(t, err)
+	require.Contains(t, string(encoded), `"timestamp":""`)
+}
+
+// TestLog_UnmarshalJSON は既定のレイアウトの timestamp を time.Time に変換することを検証する
+func TestLog_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var log model.Log
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"id","timestamp":"2025-01-02T03:04:05Z","level":"ERROR","message":"x"}`), &log))
+	require.True(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC).Equal(log.Timestamp))
+	require.Equal(t, model.LevelError, log.Level)
+
+	var logs []*model.Log
+	require.NoError(t, json.Unmarshal([]byte(`[{"timestamp":"1735787045123"},{"timestamp":""}]`), &logs))
+	require.Equal(t, "2025-01-02T03:04:05.123000000Z", model.FormatTimestamp(logs[0].Timestamp))
+	require.True(t, logs[1].Timestamp.IsZero())
+
+	err := json.Unmarshal([]byte(`{"timestamp":"yesterday"}`), &log)
+	require.ErrorIs(t, err, model.ErrInvalidTimestamp)
+}
+
+// TestDecodeLog は指定したレイアウトで timestamp を解釈することを検証する
+func TestDecodeLog(t *testing.T) {
+	t.Parallel()
+
+	log, err := model.DecodeLog([]byte(`{"timestamp":"02/01/2025 03:04","message":"x"}`), []string{"02/01/2006 15:04"})
+	require.NoError(t, err)
+	require.Equal(t, "2025-01-02T03:04:00.000000000Z", model.FormatTimestamp(log.Timestamp))
+
+	_, err = model.DecodeLog([]byte(`{"timestamp":"02/01/2025 03:04"}`), nil)
+	require.ErrorIs(t, err, model.ErrInvalidTimestamp)
+}
+
+// TestDecodeLogLenient は解釈できない timestamp をエラーにせず、元の値をメタデータに残すことを検証する
+func TestDecodeLogLenient(t *testing.T) {
+	t.Parallel()
+
+	log, err := model.DecodeLogLenient([]byte(`{"timestamp":"yesterday","message":"x","metadata":{"env":"dev"}}`))
+	require.NoError(t, err)
+	require.True(t, log.Timestamp.IsZero())
+	require.Equal(t, "x", log.Message)
+	require.Equal(t, map[string]string{"env": "dev", model.InvalidTimestampKey: "yesterday"}, log.Metadata)
+
+	log, err = model.DecodeLogLenient([]byte(`{"timestamp":"2025-01-02T03:04:05Z"}`))
+	require.NoError(t, err)
+	require.True(t, time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC).Equal(log.Timestamp))
+	require.Nil(t, log.Metadata)
+
+	_, err = model.DecodeLogLenient([]byte(`{"timestamp":`))
+	require.Error(t, err)
+}
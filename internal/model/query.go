@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// LogQuery はログ取得時の検索条件を表す構造体
+// 空文字列や time.Time のゼロ値の条件は指定なしとして扱う
+type LogQuery struct {
+	Service   string
+	Level     Level
+	StartTime time.Time
+	EndTime   time.Time
+	Limit     int32
+	Offset    int32
+}
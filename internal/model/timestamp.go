@@ -0,0 +1,109 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTimestamp は、タイムスタンプをいずれのレイアウトでも解釈できない場合のエラー
+var ErrInvalidTimestamp = errors.New("invalid timestamp")
+
+// TimestampLayout は送信・表示に使用するタイムスタンプの形式
+// RFC3339 のナノ秒精度で小数部の桁数を固定し、文字列として比較しても時刻順に並ぶようにする
+const TimestampLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// LayoutUnix は Unix 時間（秒・ミリ秒・マイクロ秒・ナノ秒を桁数で判別、小数の秒も可）を表すレイアウト名
+const LayoutUnix = "unix"
+
+// 桁数による Unix 時間の単位の判別に使用する上限（これより大きい値は次の単位とみなす）
+const (
+	maxUnixSeconds = 1e11 // 約 5138 年までを秒とみなす
+	maxUnixMillis  = 1e14
+	maxUnixMicros  = 1e17
+)
+
+// DefaultTimestampLayouts は入力されたタイムスタンプの解釈に使用する既定のレイアウト
+// タイムゾーンを含まない形式は UTC とみなす
+var DefaultTimestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	LayoutUnix,
+}
+
+// ParseTimestamp は layouts を順に試してタイムスタンプを解釈する
+// layouts が空の場合は DefaultTimestampLayouts を使用する
+func ParseTimestamp(value string, layouts []string) (time.Time, error) {
+	if len(layouts) == 0 {
+		layouts = DefaultTimestampLayouts
+	}
+
+	value = strings.TrimSpace(value)
+
+	for _, layout := range layouts {
+		if layout == LayoutUnix {
+			if parsed, ok := parseUnix(value); ok {
+				return parsed, nil
+			}
+
+			continue
+		}
+
+		if parsed, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return parsed, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidTimestamp, value)
+}
+
+// FormatTimestamp は時刻を TimestampLayout（UTC）の文字列に変換する。ゼロ値の場合は空文字列を返す
+func FormatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(TimestampLayout)
+}
+
+// parseUnix は Unix 時間の文字列を解釈する
+// 整数は桁数から秒・ミリ秒・マイクロ秒・ナノ秒を判別し、小数は秒とみなす
+func parseUnix(value string) (time.Time, bool) {
+	if value == "" || strings.HasPrefix(value, "-") {
+		return time.Time{}, false
+	}
+
+	if seconds, fraction, ok := strings.Cut(value, "."); ok {
+		sec, err := strconv.ParseInt(seconds, 10, 64)
+		if err != nil || fraction == "" || len(fraction) > 9 { //nolint:mnd // ナノ秒までの桁数
+			return time.Time{}, false
+		}
+
+		nsec, err := strconv.ParseInt((fraction + "000000000")[:9], 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		return time.Unix(sec, nsec).UTC(), true
+	}
+
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch {
+	case number < maxUnixSeconds:
+		return time.Unix(number, 0).UTC(), true
+	case number < maxUnixMillis:
+		return time.UnixMilli(number).UTC(), true
+	case number < maxUnixMicros:
+		return time.UnixMicro(number).UTC(), true
+	default:
+		return time.Unix(0, number).UTC(), true
+	}
+}
@@ -0,0 +1,70 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// TestParseTimestamp_DefaultLayouts は既定のレイアウトでよく使われる形式を解釈できることを検証する
+func TestParseTimestamp_DefaultLayouts(t *testing.T) {
+	t.Parallel()
+
+	expected := time.Date(2025, 1, 2, 3, 4, 5, 123456789, time.UTC)
+
+	cases := map[string]time.Time{
+		"2025-01-02T03:04:05.123456789Z":      expected,
+		"2025-01-02T12:04:05.123456789+09:00": expected,
+		"2025-01-02 03:04:05.123456789":       expected,
+		"2025-01-02T03:04:05.123456789":       expected,
+		"2025-01-02T03:04:05Z":                expected.Truncate(time.Second),
+		"1735787045":                          expected.Truncate(time.Second),
+		"1735787045123":                       expected.Truncate(time.Millisecond),
+		"1735787045123456":                    expected.Truncate(time.Microsecond),
+		"1735787045123456789":                 expected,
+		"1735787045.123456789":                expected,
+		"1735787045.5":                        expected.Truncate(time.Second).Add(500 * time.Millisecond),
+	}
+
+	for input, want := range cases {
+		parsed, err := model.ParseTimestamp(input, nil)
+		require.NoError(t, err, input)
+		require.True(t, want.Equal(parsed), "%s: got %s", input, parsed)
+	}
+
+	_, err := model.ParseTimestamp("02/01/2025 03:04:05", nil)
+	require.ErrorIs(t, err, model.ErrInvalidTimestamp)
+}
+
+// TestParseTimestamp_CustomLayouts は指定したレイアウトのみで解釈することを検証する
+func TestParseTimestamp_CustomLayouts(t *testing.T) {
+	t.Parallel()
+
+	layouts := []string{"02/Jan/2006:15:04:05 -0700"}
+
+	parsed, err := model.ParseTimestamp("02/Jan/2025:12:04:05 +0900", layouts)
+	require.NoError(t, err)
+	require.Equal(t, "2025-01-02T03:04:05.000000000Z", model.FormatTimestamp(parsed))
+
+	_, err = model.ParseTimestamp("1735787045", layouts)
+	require.ErrorIs(t, err, model.ErrInvalidTimestamp)
+}
+
+// TestFormatTimestamp はナノ秒精度の固定幅で出力し、文字列の順序が時刻の順序と一致することを検証する
+func TestFormatTimestamp(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	first := model.FormatTimestamp(base)
+	second := model.FormatTimestamp(base.Add(time.Nanosecond))
+	third := model.FormatTimestamp(base.Add(100 * time.Millisecond))
+
+	require.Equal(t, "2025-01-02T03:04:05.000000000Z", first)
+	require.Equal(t, "2025-01-02T03:04:05.000000001Z", second)
+	require.Less(t, first, second)
+	require.Less(t, second, third)
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// メタデータの上限
+const (
+	MaxMetadataEntries = 64        // キーの最大数
+	MaxMetadataBytes   = 16 * 1024 // キーと値の合計の最大バイト数
+)
+
+// ログの検証エラー（いずれも errors.Is(err, ErrInvalidLog) で判定できる）
+var (
+	ErrInvalidLog       = errors.New("invalid log")
+	ErrMissingService   = errors.New("service is required")
+	ErrMissingMessage   = errors.New("message is required")
+	ErrMissingTimestamp = errors.New("timestamp is required")
+	ErrMetadataTooLarge = errors.New("metadata is too large")
+)
+
+// Normalize は送信前にログを正規化し、未設定の項目を補完する
+//   - Level: 別名を正規のレベルに変換する（未設定の場合は INFO、未知の表記はそのまま残し Validate で検出する）
+//   - ID / TraceID: 未設定の場合は UUID を生成する
+//   - Timestamp: 未設定の場合は now を使用し、UTC に変換する
+//   - Service: 前後の空白を取り除く
+func (l *Log) Normalize(now time.Time) {
+	if l.ID == "" {
+		l.ID = uuid.NewString()
+	}
+
+	if l.TraceID == "" {
+		l.TraceID = uuid.NewString()
+	}
+
+	if l.Timestamp.IsZero() {
+		l.Timestamp = now
+	}
+
+	l.Timestamp = l.Timestamp.UTC()
+
+	if strings.TrimSpace(string(l.Level)) == "" {
+		l.Level = LevelInfo
+	} else if level, err := ParseLevel(string(l.Level)); err == nil {
+		l.Level = level
+	}
+
+	l.Service = strings.TrimSpace(l.Service)
+}
+
+// Validate はログが送信可能かを検証し、問題があればすべてをまとめて返す
+func (l *Log) Validate() error {
+	var problems []error
+
+	if _, err := ParseLevel(string(l.Level)); err != nil {
+		problems = append(problems, err)
+	}
+
+	if strings.TrimSpace(l.Service) == "" {
+		problems = append(problems, ErrMissingService)
+	}
+
+	if strings.TrimSpace(l.Message) == "" {
+		problems = append(problems, ErrMissingMessage)
+	}
+
+	if l.Timestamp.IsZero() {
+		problems = append(problems, ErrMissingTimestamp)
+	}
+
+	if err := validateMetadata(l.Metadata); err != nil {
+		problems = append(problems, err)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidLog, errors.Join(problems...))
+	}
+
+	return nil
+}
+
+// validateMetadata はメタデータのキー数と合計サイズが上限以内かを検証する
+func validateMetadata(metadata map[string]string) error {
+	if len(metadata) > MaxMetadataEntries {
+		return fmt.Errorf("%w: %d keys (max %d)", ErrMetadataTooLarge, len(metadata), MaxMetadataEntries)
+	}
+
+	size := 0
+	for key, value := range metadata {
+		size += len(key) + len(value)
+	}
+
+	if size > MaxMetadataBytes {
+		return fmt.Errorf("%w: %d bytes (max %d)", ErrMetadataTooLarge, size, MaxMetadataBytes)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package model_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// TestLog_Normalize は未設定の項目が補完され、レベルが正規化されることを検証する
+func TestLog_Normalize(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	log := &model.Log{ID: "", TraceID: "", Timestamp: time.Time{}, Level: "warning", Service: " billing ", Message: "paid", Metadata: nil}
+
+	log.Normalize(now)
+
+	require.NotEmpty(t, log.ID)
+	require.NotEmpty(t, log.TraceID)
+	require.Equal(t, now, log.Timestamp)
+	require.Equal(t, model.LevelWarn, log.Level)
+	require.Equal(t, "billing", log.Service)
+	require.NoError(t, log.Validate())
+
+	// 設定済みの項目は変更しない
+	id := log.ID
+
+	log.Normalize(now.Add(time.Hour))
+	require.Equal(t, id, log.ID)
+	require.Equal(t, now, log.Timestamp)
+
+	// レベル未設定は INFO とする
+	empty := &model.Log{ID: "", TraceID: "", Timestamp: time.Time{}, Level: "", Service: "billing", Message: "paid", Metadata: nil}
+	empty.Normalize(now)
+	require.Equal(t, model.LevelInfo, empty.Level)
+}
+
+// TestLog_Validate は不正な項目がすべてまとめて報告されることを検証する
+func TestLog_Validate(t *testing.T) {
+	t.Parallel()
+
+	log := &model.Log{
+		ID:        "id",
+		TraceID:   "trace",
+		Timestamp: time.Time{},
+		Level:     "VERBOSE",
+		Service:   " ",
+		Message:   "",
+		Metadata:  nil,
+	}
+
+	err := log.Validate()
+	require.ErrorIs(t, err, model.ErrInvalidLog)
+	require.ErrorIs(t, err, model.ErrUnknownLevel)
+	require.ErrorIs(t, err, model.ErrMissingService)
+	require.ErrorIs(t, err, model.ErrMissingMessage)
+	require.ErrorIs(t, err, model.ErrMissingTimestamp)
+}
+
+// TestLog_ValidateMetadata はメタデータのキー数・サイズの上限を検証する
+func TestLog_ValidateMetadata(t *testing.T) {
+	t.Parallel()
+
+	log := &model.Log{ID: "", TraceID: "", Timestamp: time.Time{}, Level: "", Service: "billing", Message: "paid", Metadata: nil}
+	log.Normalize(time.Now())
+
+	log.Metadata = map[string]string{}
+	for i := range model.MaxMetadataEntries + 1 {
+		log.Metadata["key"+strconv.Itoa(i)] = "value"
+	}
+
+	require.ErrorIs(t, log.Validate(), model.ErrMetadataTooLarge)
+
+	log.Metadata = map[string]string{"payload": strings.Repeat("x", model.MaxMetadataBytes)}
+	require.ErrorIs(t, log.Validate(), model.ErrMetadataTooLarge)
+
+	log.Metadata = map[string]string{"env": "dev"}
+	require.NoError(t, log.Validate())
+}
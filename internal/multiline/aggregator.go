@@ -0,0 +1,230 @@
+package multiline
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EmitFunc は確定したイベント（改行で連結した行）を受け取るコールバック
+// エラーを返した場合、イベントは破棄されずに次の確定の機会に再度渡される
+type EmitFunc func(ctx context.Context, event string) error
+
+// Aggregator は Rule に従って行をイベントにまとめ、確定したイベントを EmitFunc に渡す
+// イベントは次のイベントの開始、MaxLines / MaxBytes の超過、FlushTimeout の経過、Flush / Close のいずれかで確定する
+// EmitFunc は同時に複数呼び出されることはない
+type Aggregator struct {
+	rule Rule
+	emit EmitFunc
+
+	mutex      sync.Mutex // 以下のフィールドを保護し、EmitFunc の呼び出しを直列化する
+	lines      []string
+	size       int
+	offset     int64 // バッファ中のイベントの先頭行の位置（AddAt で指定した値）
+	timer      *time.Timer
+	timerCtx   context.Context //nolint:containedctx // タイムアウトによる確定時に最後の Add のコンテキストを使用する
+	generation uint64          // Add のたびに増やし、古いタイマーによる確定を防ぐ
+	closed     bool
+}
+
+// NewAggregator は Aggregator を生成する
+func NewAggregator(rule Rule, emit EmitFunc) *Aggregator {
+	return &Aggregator{
+		rule:       rule,
+		emit:       emit,
+		mutex:      sync.Mutex{},
+		lines:      nil,
+		size:       0,
+		offset:     0,
+		timer:      nil,
+		timerCtx:   nil,
+		generation: 0,
+		closed:     false,
+	}
+}
+
+// Add は 1 行を追加する
+// 行が新しいイベントを開始する場合や上限を超える場合は、先にバッファ中のイベントを確定する
+// 確定したイベントの EmitFunc が失敗した場合は、line を追加せずにそのエラーを返す
+func (a *Aggregator) Add(ctx context.Context, line string) error {
+	return a.AddAt(ctx, line, 0)
+}
+
+// AddAt は入力元での位置（ファイル内の行の先頭の位置など）とともに 1 行を追加する
+// 位置は Pending で確定していないイベントの先頭行の位置を返すために使用する
+func (a *Aggregator) AddAt(ctx context.Context, line string, offset int64) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if len(a.lines) > 0 && (a.rule.startsEvent(line) || a.exceeds(line)) {
+		if err := a.flushLocked(ctx); err != nil {
+			return err
+		}
+	}
+
+	if len(a.lines) == 0 {
+		a.offset = offset
+	}
+
+	a.lines = append(a.lines, line)
+	a.size += len(line)
+	a.generation++
+	a.scheduleLocked(ctx)
+
+	return nil
+}
+
+// Pending はバッファ中のイベントの先頭行の位置を返す（バッファが空の場合は false）
+// 確定に失敗したイベントも、確定するまではバッファ中として扱う
+func (a *Aggregator) Pending() (int64, bool) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.offset, len(a.lines) > 0
+}
+
+// Flush はバッファ中のイベントを確定する
+func (a *Aggregator) Flush(ctx context.Context) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	return a.flushLocked(ctx)
+}
+
+// Close はタイマーを停止し、バッファ中のイベントを確定する
+func (a *Aggregator) Close(ctx context.Context) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.closed = true
+
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+
+	return a.flushLocked(ctx)
+}
+
+// exceeds は line を追加するとイベントの上限を超えるかを返す（改行の分を含む）
+func (a *Aggregator) exceeds(line string) bool {
+	if a.rule.MaxLines > 0 && len(a.lines)+1 > a.rule.MaxLines {
+		return true
+	}
+
+	return a.rule.MaxBytes > 0 && a.size+len(a.lines)+len(line) > a.rule.MaxBytes
+}
+
+// flushLocked はバッファ中のイベントを EmitFunc に渡す（mutex を取得済みで呼び出す）
+// 失敗した場合はバッファを保持する
+func (a *Aggregator) flushLocked(ctx context.Context) error {
+	if len(a.lines) == 0 {
+		return nil
+	}
+
+	if err := a.emit(ctx, strings.Join(a.lines, "\n")); err != nil {
+		return err
+	}
+
+	a.lines = nil
+	a.size = 0
+
+	return nil
+}
+
+// scheduleLocked は FlushTimeout 後にイベントを確定するタイマーを設定する（mutex を取得済みで呼び出す）
+func (a *Aggregator) scheduleLocked(ctx context.Context) {
+	if a.rule.FlushTimeout <= 0 || a.closed {
+		return
+	}
+
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+
+	generation := a.generation
+	a.timerCtx = ctx
+	a.timer = time.AfterFunc(a.rule.FlushTimeout, func() {
+		a.onTimeout(generation)
+	})
+}
+
+// onTimeout はタイマーの期限切れ時にイベントを確定する
+// 期限切れまでに行が追加されていた場合は何もせず、確定に失敗した場合は再度タイマーを設定する
+func (a *Aggregator) onTimeout(generation uint64) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed || generation != a.generation {
+		return
+	}
+
+	if err := a.flushLocked(a.timerCtx); err != nil {
+		a.scheduleLocked(a.timerCtx)
+	}
+}
+
+// Group はキー（ファイルのパスなど）ごとに Aggregator を保持し、入力元ごとに行をまとめる
+type Group struct {
+	rule Rule
+	emit func(ctx context.Context, key, event string) error
+
+	mutex       sync.Mutex
+	aggregators map[string]*Aggregator
+}
+
+// NewGroup は Group を生成する。emit には確定したイベントとその入力元のキーが渡される
+func NewGroup(rule Rule, emit func(ctx context.Context, key, event string) error) *Group {
+	return &Group{rule: rule, emit: emit, mutex: sync.Mutex{}, aggregators: map[string]*Aggregator{}}
+}
+
+// Add はキーに対応する Aggregator に入力元での位置とともに 1 行を追加する（Aggregator.AddAt を参照）
+func (g *Group) Add(ctx context.Context, key, line string, offset int64) error {
+	return g.aggregator(key).AddAt(ctx, line, offset)
+}
+
+// Pending はキーに対応する Aggregator のバッファ中のイベントの先頭行の位置を返す（Aggregator.Pending を参照）
+func (g *Group) Pending(key string) (int64, bool) {
+	g.mutex.Lock()
+	aggregator, ok := g.aggregators[key]
+	g.mutex.Unlock()
+
+	if !ok {
+		return 0, false
+	}
+
+	return aggregator.Pending()
+}
+
+// Close はすべての Aggregator を閉じ、バッファ中のイベントを確定する
+// 失敗したキーがあっても残りの Aggregator を閉じ、最初のエラーを返す
+func (g *Group) Close(ctx context.Context) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	var firstErr error
+
+	for _, aggregator := range g.aggregators {
+		if err := aggregator.Close(ctx); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// aggregator はキーに対応する Aggregator を返す（なければ生成する）
+func (g *Group) aggregator(key string) *Aggregator {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	aggregator, ok := g.aggregators[key]
+	if !ok {
+		aggregator = NewAggregator(g.rule, func(ctx context.Context, event string) error {
+			return g.emit(ctx, key, event)
+		})
+		g.aggregators[key] = aggregator
+	}
+
+	return aggregator
+}
@@ -0,0 +1,214 @@
+package multiline_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/multiline"
+)
+
+// 共通エラー定義
+var errEmitFailed = errors.New("emit failed")
+
+// recorder は確定したイベントを記録するテスト用の EmitFunc を提供する
+type recorder struct {
+	mutex  sync.Mutex
+	events []string
+	fail   bool
+}
+
+// emit は fail が true の場合は失敗し、それ以外はイベントを記録する
+func (r *recorder) emit(_ context.Context, event string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.fail {
+		return errEmitFailed
+	}
+
+	r.events = append(r.events, event)
+
+	return nil
+}
+
+// snapshot は記録したイベントを返す
+func (r *recorder) snapshot() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]string(nil), r.events...)
+}
+
+// setFail は emit の成否を切り替える
+func (r *recorder) setFail(fail bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.fail = fail
+}
+
+// indentRule はインデントされた行を直前の行に続ける規則を返す
+func indentRule(t *testing.T) multiline.Rule {
+	t.Helper()
+
+	rule, err := multiline.NewRule("", `^\s`)
+	require.NoError(t, err)
+
+	rule.FlushTimeout = 0
+
+	return *rule
+}
+
+// TestAggregator_MaxLinesAndBytes は上限を超える行で新しいイベントを開始することを検証する
+func TestAggregator_MaxLinesAndBytes(t *testing.T) {
+	t.Parallel()
+
+	rule := indentRule(t)
+	rule.MaxLines = 3
+	rule.MaxBytes = 0
+
+	rec := &recorder{mutex: sync.Mutex{}, events: nil, fail: false}
+	aggregator := multiline.NewAggregator(rule, rec.emit)
+
+	for _, line := range []string{"head", " 1", " 2", " 3", " 4"} {
+		require.NoError(t, aggregator.Add(context.Background(), line))
+	}
+
+	require.NoError(t, aggregator.Close(context.Background()))
+	require.Equal(t, []string{"head\n 1\n 2", " 3\n 4"}, rec.snapshot())
+
+	// バイト数の上限は連結後の改行を含めて判定する
+	rule.MaxLines = 0
+	rule.MaxBytes = 9
+
+	rec = &recorder{mutex: sync.Mutex{}, events: nil, fail: false}
+	aggregator = multiline.NewAggregator(rule, rec.emit)
+
+	for _, line := range []string{"head", " ab", " cd", " this line is longer than the limit"} {
+		require.NoError(t, aggregator.Add(context.Background(), line))
+	}
+
+	require.NoError(t, aggregator.Close(context.Background()))
+	require.Equal(t, []string{"head\n ab", " cd", " this line is longer than the limit"}, rec.snapshot())
+}
+
+// TestAggregator_FlushTimeout は最後の行から FlushTimeout が経過するとイベントを確定することを検証する
+func TestAggregator_FlushTimeout(t *testing.T) {
+	t.Parallel()
+
+	rule := indentRule(t)
+	rule.FlushTimeout = 20 * time.Millisecond
+
+	rec := &recorder{mutex: sync.Mutex{}, events: nil, fail: false}
+	aggregator := multiline.NewAggregator(rule, rec.emit)
+
+	require.NoError(t, aggregator.Add(context.Background(), "panic"))
+	require.NoError(t, aggregator.Add(context.Background(), "  frame"))
+
+	require.Eventually(t, func() bool { return len(rec.snapshot()) == 1 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, []string{"panic\n  frame"}, rec.snapshot())
+
+	// 確定済みのイベントに継続行は追加されない
+	require.NoError(t, aggregator.Add(context.Background(), "  late"))
+	require.NoError(t, aggregator.Close(context.Background()))
+	require.Equal(t, []string{"panic\n  frame", "  late"}, rec.snapshot())
+}
+
+// TestAggregator_EmitFailure は確定に失敗したイベントを保持し、次の機会に再度渡すことを検証する
+func TestAggregator_EmitFailure(t *testing.T) {
+	t.Parallel()
+
+	rec := &recorder{mutex: sync.Mutex{}, events: nil, fail: false}
+	aggregator := multiline.NewAggregator(indentRule(t), rec.emit)
+
+	require.NoError(t, aggregator.Add(context.Background(), "first"))
+	require.NoError(t, aggregator.Add(context.Background(), "  detail"))
+
+	rec.setFail(true)
+	require.ErrorIs(t, aggregator.Add(context.Background(), "second"), errEmitFailed)
+
+	// 失敗した行を再度追加すると、保持していたイベントから確定する
+	rec.setFail(false)
+	require.NoError(t, aggregator.Add(context.Background(), "second"))
+	require.NoError(t, aggregator.Close(context.Background()))
+	require.Equal(t, []string{"first\n  detail", "second"}, rec.snapshot())
+}
+
+// TestAggregator_Pending は確定していないイベントの先頭行の位置を返し、確定後は保留なしになることを検証する
+func TestAggregator_Pending(t *testing.T) {
+	t.Parallel()
+
+	rec := &recorder{mutex: sync.Mutex{}, events: nil, fail: false}
+	aggregator := multiline.NewAggregator(indentRule(t), rec.emit)
+
+	_, pending := aggregator.Pending()
+	require.False(t, pending)
+
+	require.NoError(t, aggregator.AddAt(context.Background(), "first", 0))
+	require.NoError(t, aggregator.AddAt(context.Background(), "  detail", 6))
+
+	offset, pending := aggregator.Pending()
+	require.True(t, pending)
+	require.Equal(t, int64(0), offset)
+
+	// 次のイベントの開始で確定した後は、新しいイベントの先頭行の位置を返す
+	require.NoError(t, aggregator.AddAt(context.Background(), "second", 15))
+
+	offset, pending = aggregator.Pending()
+	require.True(t, pending)
+	require.Equal(t, int64(15), offset)
+
+	// 確定に失敗したイベントは保留中のまま
+	rec.setFail(true)
+	require.ErrorIs(t, aggregator.Close(context.Background()), errEmitFailed)
+
+	offset, pending = aggregator.Pending()
+	require.True(t, pending)
+	require.Equal(t, int64(15), offset)
+
+	rec.setFail(false)
+	require.NoError(t, aggregator.Flush(context.Background()))
+
+	_, pending = aggregator.Pending()
+	require.False(t, pending)
+}
+
+// TestGroup はキーごとに行をまとめることを検証する
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mutex  sync.Mutex
+		events = map[string][]string{}
+	)
+
+	group := multiline.NewGroup(indentRule(t), func(_ context.Context, key, event string) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		events[key] = append(events[key], event)
+
+		return nil
+	})
+
+	require.NoError(t, group.Add(context.Background(), "a.log", "a1", 0))
+	require.NoError(t, group.Add(context.Background(), "b.log", "b1", 0))
+	require.NoError(t, group.Add(context.Background(), "a.log", "  a2", 3))
+	require.NoError(t, group.Add(context.Background(), "b.log", "  b2", 3))
+
+	offset, pending := group.Pending("a.log")
+	require.True(t, pending)
+	require.Equal(t, int64(0), offset)
+
+	_, pending = group.Pending("c.log")
+	require.False(t, pending)
+
+	require.NoError(t, group.Close(context.Background()))
+
+	require.Equal(t, map[string][]string{"a.log": {"a1\n  a2"}, "b.log": {"b1\n  b2"}}, events)
+}
@@ -0,0 +1,129 @@
+// Package multiline は、スタックトレースなど複数行にわたる出力を 1 件のイベントにまとめる集約処理を提供する
+package multiline
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// プリセット名（Preset に指定する値）
+const (
+	PresetJava   = "java"
+	PresetPython = "python"
+	PresetGo     = "go"
+)
+
+// Rule の既定の上限
+const (
+	DefaultMaxLines     = 1000
+	DefaultMaxBytes     = 256 * 1024
+	DefaultFlushTimeout = time.Second
+)
+
+// 規則に関するエラー
+var (
+	ErrUnknownPreset  = errors.New("unknown multiline preset")
+	ErrInvalidPattern = errors.New("invalid multiline pattern")
+	ErrNoPattern      = errors.New("multiline rule requires a start or continuation pattern")
+)
+
+// Rule は行をイベントにまとめる規則を保持する構造体
+// 行は以下の順に判定し、新しいイベントを開始しない行は直前のイベントに追加する
+//   - Start に一致する行は新しいイベントを開始する
+//   - Continuation が指定されている場合、Continuation に一致しない行は新しいイベントを開始する
+//   - Continuation が指定されていない場合、Start に一致しない行は直前のイベントに追加する
+type Rule struct {
+	Start        *regexp.Regexp // イベントの開始行のパターン（nil の場合は使用しない）
+	Continuation *regexp.Regexp // 直前のイベントに続く行のパターン（nil の場合は使用しない）
+	MaxLines     int            // 1 イベントの最大行数（0 以下は無制限）
+	MaxBytes     int            // 1 イベントの最大バイト数（0 以下は無制限）
+	FlushTimeout time.Duration  // 最後の行からこの時間が経過したらイベントを確定する（0 以下は次のイベントの開始まで待つ）
+}
+
+// presets は組み込みのプリセットの開始・継続パターン
+var presets = map[string]struct{ start, continuation string }{ //nolint:gochecknoglobals // 組み込みのプリセットの定義
+	// 例外のクラス名の行、インデントされた "at ..." / "... N more"、Caused by: / Suppressed: を直前の行に続ける
+	PresetJava: {
+		start: "",
+		continuation: `^\s+at\s|^\s+\.\.\. \d+ (?:more|common frames omitted)|^\s*Caused by:|^\s*Suppressed:|` +
+			`^[\w$.]+(?:Exception|Error|Throwable)(?::|$)`,
+	},
+	// Traceback の見出し、インデントされた行、連鎖した例外の説明、例外クラス名の行を直前の行に続ける
+	PresetPython: {
+		start: "",
+		continuation: `^\s|^Traceback \(most recent call last\):|^During handling of the above exception|` +
+			`^The above exception was the direct cause|^[\w.]+(?:Error|Exception|Warning|Exit|Interrupt|Iteration)(?::|$)`,
+	},
+	// panic: / fatal error: で開始し、goroutine の見出し、関数呼び出しの行、インデントされた行などを続ける
+	PresetGo: {
+		start:        `^(?:panic|fatal error): `,
+		continuation: `^\s|^goroutine \d+ \[|^\[signal |^[^\s(]+\(.*\)$|^created by |^exit status \d+$`,
+	},
+}
+
+// NewRule は開始・継続パターンから Rule を生成する（空のパターンは使用しない）
+// 上限とタイムアウトは既定値を設定する
+func NewRule(start, continuation string) (*Rule, error) {
+	if start == "" && continuation == "" {
+		return nil, ErrNoPattern
+	}
+
+	rule := &Rule{
+		Start:        nil,
+		Continuation: nil,
+		MaxLines:     DefaultMaxLines,
+		MaxBytes:     DefaultMaxBytes,
+		FlushTimeout: DefaultFlushTimeout,
+	}
+
+	var err error
+
+	if rule.Start, err = compile(start); err != nil {
+		return nil, err
+	}
+
+	if rule.Continuation, err = compile(continuation); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// Preset は組み込みのプリセットの Rule を生成する
+func Preset(name string) (*Rule, error) {
+	preset, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (must be %s, %s or %s)", ErrUnknownPreset, name, PresetJava, PresetPython, PresetGo)
+	}
+
+	return NewRule(preset.start, preset.continuation)
+}
+
+// startsEvent は line が新しいイベントを開始するかを返す
+func (r *Rule) startsEvent(line string) bool {
+	if r.Start != nil && r.Start.MatchString(line) {
+		return true
+	}
+
+	if r.Continuation != nil {
+		return !r.Continuation.MatchString(line)
+	}
+
+	return false
+}
+
+// compile はパターンをコンパイルする（空の場合は nil）
+func compile(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil //nolint:nilnil // 空のパターンは使用しない
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPattern, err)
+	}
+
+	return compiled, nil
+}
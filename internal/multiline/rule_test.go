@@ -0,0 +1,128 @@
+package multiline_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/multiline"
+)
+
+// javaTrace は Java の例外のスタックトレースを含む出力
+const javaTrace = `2025-01-02 03:04:05 ERROR [main] c.e.Billing - payment failed
+java.lang.IllegalStateException: card declined
+	at com.example.Billing.charge(Billing.java:42)
+	at com.example.Main.main(Main.java:10)
+Caused by: java.io.IOException: timeout
+	at com.example.Gateway.call(Gateway.java:7)
+	... 2 more
+2025-01-02 03:04:06 INFO [main] c.e.Billing - retrying`
+
+// pythonTrace は Python の連鎖した例外のトレースバックを含む出力
+const pythonTrace = `ERROR:root:request failed
+Traceback (most recent call last):
+  File "app.py", line 3, in <module>
+    main()
+KeyError: 'user'
+During handling of the above exception, another exception occurred:
+Traceback (most recent call last):
+  File "app.py", line 5, in <module>
+    raise RuntimeError("boom")
+RuntimeError: boom
+INFO:root:shutting down`
+
+// goTrace は Go の panic の出力
+const goTrace = `starting server
+panic: runtime error: index out of range [3] with length 3
+
+goroutine 1 [running]:
+main.handler(0xc000012345, 0x3)
+	/src/main.go:12 +0x1d
+main.(*Server).ServeHTTP(...)
+	/src/server.go:40
+created by net/http.(*Server).Serve in goroutine 7
+	/usr/local/go/src/net/http/server.go:3285 +0x4b4
+exit status 2
+restarting`
+
+// collect は rule で input の各行をまとめたイベントを返す（空行は読み飛ばす）
+func collect(t *testing.T, rule *multiline.Rule, input string) []string {
+	t.Helper()
+
+	var events []string
+
+	aggregator := multiline.NewAggregator(*rule, func(_ context.Context, event string) error {
+		events = append(events, event)
+
+		return nil
+	})
+
+	for line := range strings.SplitSeq(input, "\n") {
+		if strings.TrimSpace(line) != "" {
+			require.NoError(t, aggregator.Add(context.Background(), line))
+		}
+	}
+
+	require.NoError(t, aggregator.Close(context.Background()))
+
+	return events
+}
+
+// TestPreset は組み込みのプリセットで 1 つのスタックトレースが 1 件のイベントになることを検証する
+func TestPreset(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		preset string
+		input  string
+		want   []int // 各イベントの行数
+	}{
+		{multiline.PresetJava, javaTrace, []int{7, 1}},
+		{multiline.PresetPython, pythonTrace, []int{10, 1}},
+		{multiline.PresetGo, goTrace, []int{1, 9, 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.preset, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := multiline.Preset(test.preset)
+			require.NoError(t, err)
+
+			events := collect(t, rule, test.input)
+
+			lines := make([]int, 0, len(events))
+			for _, event := range events {
+				lines = append(lines, strings.Count(event, "\n")+1)
+			}
+
+			require.Equal(t, test.want, lines, strings.Join(events, "\n----\n"))
+		})
+	}
+
+	_, err := multiline.Preset("ruby")
+	require.ErrorIs(t, err, multiline.ErrUnknownPreset)
+}
+
+// TestNewRule はパターンの組み合わせごとに新しいイベントを開始する行を判定することを検証する
+func TestNewRule(t *testing.T) {
+	t.Parallel()
+
+	input := "2025-01-02 first\n  detail\nplain\n2025-01-03 second\n  more"
+
+	startOnly, err := multiline.NewRule(`^\d{4}-`, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"2025-01-02 first\n  detail\nplain", "2025-01-03 second\n  more"}, collect(t, startOnly, input))
+
+	continuationOnly, err := multiline.NewRule("", `^\s`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2025-01-02 first\n  detail", "plain", "2025-01-03 second\n  more"}, collect(t, continuationOnly, input))
+
+	_, err = multiline.NewRule("", "")
+	require.ErrorIs(t, err, multiline.ErrNoPattern)
+
+	_, err = multiline.NewRule(`(`, "")
+	require.ErrorIs(t, err, multiline.ErrInvalidPattern)
+}
@@ -0,0 +1,82 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// metadataColumnPrefix はメタデータを展開した列の見出しに付与する接頭辞
+const metadataColumnPrefix = "metadata."
+
+// csvHeader はメタデータ以外の列の見出し
+var csvHeader = []string{"id", "traceId", "timestamp", "level", "service", "message"} //nolint:gochecknoglobals // 不変の見出し
+
+// csvPrinter はログを CSV 形式で出力する
+// メタデータはキーごとに metadata.<キー> の列として展開する
+type csvPrinter struct {
+	writer io.Writer
+	logs   []*model.Log
+}
+
+// newCSVPrinter は csvPrinter を生成する
+func newCSVPrinter(writer io.Writer) *csvPrinter {
+	return &csvPrinter{writer: writer, logs: make([]*model.Log, 0)}
+}
+
+// Print はログを出力対象に追加する
+func (p *csvPrinter) Print(logs []*model.Log) error {
+	p.logs = append(p.logs, logs...)
+
+	return nil
+}
+
+// Flush は追加されたログに含まれる全メタデータキーを列として CSV を出力する
+func (p *csvPrinter) Flush() error {
+	keySet := make(map[string]struct{})
+
+	for _, log := range p.logs {
+		for key := range log.Metadata {
+			keySet[key] = struct{}{}
+		}
+	}
+
+	keys := slices.Sorted(maps.Keys(keySet))
+
+	header := slices.Clone(csvHeader)
+	for _, key := range keys {
+		header = append(header, metadataColumnPrefix+key)
+	}
+
+	writer := csv.NewWriter(p.writer)
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	for _, log := range p.logs {
+		record := []string{
+			log.ID, log.TraceID, model.FormatTimestamp(log.Timestamp), string(log.Level), log.Service, log.Message,
+		}
+		for _, key := range keys {
+			record = append(record, log.Metadata[key])
+		}
+
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	p.logs = p.logs[:0]
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// 出力形式（--output に指定する値）
+const (
+	FormatTable  = "table"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+	FormatRaw    = "raw"
+)
+
+// ErrUnknownFormat は、未対応の出力形式が指定された場合のエラー
+var ErrUnknownFormat = errors.New("unknown output format")
+
+// Printer はログを出力形式に従って書き出すインターフェース
+// Print は取得したログを順に受け取り、Flush で出力を完了する
+// 全件をそろえてから書き出す形式（table / json / csv）は Flush まで出力を保留する
+type Printer interface {
+	Print(logs []*model.Log) error
+	Flush() error
+}
+
+// New は format に対応する Printer を生成する
+//
+//nolint:ireturn // 出力形式を呼び出し側から隠蔽するためインターフェースを返す
+func New(format string, writer io.Writer) (Printer, error) {
+	switch format {
+	case FormatTable:
+		return newTablePrinter(writer), nil
+	case FormatJSON:
+		return &jsonPrinter{writer: writer, logs: make([]*model.Log, 0)}, nil
+	case FormatNDJSON:
+		return &ndjsonPrinter{encoder: json.NewEncoder(writer)}, nil
+	case FormatCSV:
+		return newCSVPrinter(writer), nil
+	case FormatRaw:
+		return &rawPrinter{writer: writer}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+}
+
+// jsonPrinter はログ全件を 1 つの JSON 配列として出力する
+type jsonPrinter struct {
+	writer io.Writer
+	logs   []*model.Log
+}
+
+// Print はログを出力対象に追加する
+func (p *jsonPrinter) Print(logs []*model.Log) error {
+	p.logs = append(p.logs, logs...)
+
+	return nil
+}
+
+// Flush は追加されたログを JSON 配列として出力する
+func (p *jsonPrinter) Flush() error {
+	encoder := json.NewEncoder(p.writer)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(p.logs); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+
+	p.logs = p.logs[:0]
+
+	return nil
+}
+
+// ndjsonPrinter はログを 1 行 1 件の JSON として逐次出力する
+type ndjsonPrinter struct {
+	encoder *json.Encoder
+}
+
+// Print はログを 1 件ずつ JSON の行として出力する
+func (p *ndjsonPrinter) Print(logs []*model.Log) error {
+	for _, log := range logs {
+		if err := p.encoder.Encode(log); err != nil {
+			return fmt.Errorf("failed to write NDJSON: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Flush は何もしない（Print の時点で出力済み）
+func (p *ndjsonPrinter) Flush() error {
+	return nil
+}
+
+// rawPrinter はログのメッセージのみを 1 行ずつ逐次出力する
+type rawPrinter struct {
+	writer io.Writer
+}
+
+// Print はログのメッセージを 1 行ずつ出力する
+func (p *rawPrinter) Print(logs []*model.Log) error {
+	for _, log := range logs {
+		if _, err := fmt.Fprintln(p.writer, log.Message); err != nil {
+			return fmt.Errorf("failed to write message: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Flush は何もしない（Print の時点で出力済み）
+func (p *rawPrinter) Flush() error {
+	return nil
+}
@@ -0,0 +1,94 @@
+package output_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	"github.com/KeitaShimura/logs-collector-client/internal/output"
+)
+
+// testLogs は出力形式の検証に使用するログ
+func testLogs() []*model.Log {
+	return []*model.Log{
+		{
+			ID:        "id-1",
+			TraceID:   "trace-1",
+			Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			Level:     "INFO",
+			Service:   "api",
+			Message:   "hello, world",
+			Metadata:  map[string]string{"host": "web-1", "env": "prod"},
+		},
+		{
+			ID:        "id-2",
+			TraceID:   "",
+			Timestamp: time.Date(2025, 1, 2, 3, 4, 6, 0, time.UTC),
+			Level:     "ERROR",
+			Service:   "worker",
+			Message:   strings.Repeat("x", 100) + "\nstack",
+			Metadata:  map[string]string{"region": "ap-northeast-1"},
+		},
+	}
+}
+
+// render は format で testLogs を出力した結果を返す
+func render(t *testing.T, format string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	printer, err := output.New(format, &buf)
+	require.NoError(t, err)
+	require.NoError(t, printer.Print(testLogs()))
+	require.NoError(t, printer.Flush())
+
+	return buf.String()
+}
+
+// TestTable は列がそろい、長いメッセージが省略されることを検証する
+func TestTable(t *testing.T) {
+	t.Parallel()
+
+	lines := strings.Split(strings.TrimRight(render(t, output.FormatTable), "\n"), "\n")
+	require.Len(t, lines, 3)
+
+	column := strings.Index(lines[0], "LEVEL")
+	require.Equal(t, "INFO", lines[1][column:column+4])
+	require.Equal(t, "ERROR", lines[2][column:column+5])
+
+	require.Contains(t, lines[1], "env=prod host=web-1")
+	require.Contains(t, lines[2], strings.Repeat("x", 79)+"…")
+	require.NotContains(t, lines[2], "stack")
+}
+
+// TestCSV はメタデータがキーごとの列に展開されることを検証する
+func TestCSV(t *testing.T) {
+	t.Parallel()
+
+	lines := strings.Split(strings.TrimRight(render(t, output.FormatCSV), "\n"), "\n")
+
+	require.Equal(t, "id,traceId,timestamp,level,service,message,metadata.env,metadata.host,metadata.region", lines[0])
+	require.Equal(t, `id-1,trace-1,2025-01-02T03:04:05.000000000Z,INFO,api,"hello, world",prod,web-1,`, lines[1])
+	require.True(t, strings.HasSuffix(lines[len(lines)-1], `stack",,,ap-northeast-1`))
+}
+
+// TestJSONFormats は JSON / NDJSON / raw の出力を検証する
+func TestJSONFormats(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, strings.HasPrefix(render(t, output.FormatJSON), "[\n  {\n    \"id\": \"id-1\""))
+
+	ndjson := strings.Split(strings.TrimRight(render(t, output.FormatNDJSON), "\n"), "\n")
+	require.Len(t, ndjson, 2)
+	require.True(t, strings.HasPrefix(ndjson[1], `{"id":"id-2"`))
+
+	require.Equal(t, "hello, world\n"+strings.Repeat("x", 100)+"\nstack\n", render(t, output.FormatRaw))
+
+	_, err := output.New("yaml", &bytes.Buffer{})
+	require.ErrorIs(t, err, output.ErrUnknownFormat)
+}
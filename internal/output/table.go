@@ -0,0 +1,98 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// 表形式の列幅の上限（超過分は省略記号に置き換える）
+const (
+	maxMessageWidth  = 80
+	maxMetadataWidth = 40
+	ellipsis         = "…"
+)
+
+// tableHeader は表形式の見出し行
+var tableHeader = []string{"TIMESTAMP", "LEVEL", "SERVICE", "ID", "MESSAGE", "METADATA"} //nolint:gochecknoglobals // 不変の見出し
+
+// tablePrinter はログを列をそろえた表形式で出力する
+// Flush を繰り返し呼ぶ場合（--follow）は見出し行を最初の 1 回のみ出力する
+type tablePrinter struct {
+	writer        io.Writer
+	logs          []*model.Log
+	headerWritten bool
+}
+
+// newTablePrinter は tablePrinter を生成する
+func newTablePrinter(writer io.Writer) *tablePrinter {
+	return &tablePrinter{writer: writer, logs: make([]*model.Log, 0), headerWritten: false}
+}
+
+// Print はログを出力対象に追加する
+func (p *tablePrinter) Print(logs []*model.Log) error {
+	p.logs = append(p.logs, logs...)
+
+	return nil
+}
+
+// Flush は追加されたログの列幅をそろえて出力する
+func (p *tablePrinter) Flush() error {
+	table := tabwriter.NewWriter(p.writer, 0, 0, 2, ' ', 0) //nolint:mnd // 列間の余白
+
+	if !p.headerWritten {
+		fmt.Fprintln(table, strings.Join(tableHeader, "\t"))
+
+		p.headerWritten = true
+	}
+
+	for _, log := range p.logs {
+		fmt.Fprintln(table, strings.Join([]string{
+			cell(model.FormatTimestamp(log.Timestamp), 0),
+			cell(string(log.Level), 0),
+			cell(log.Service, 0),
+			cell(log.ID, 0),
+			cell(log.Message, maxMessageWidth),
+			cell(formatMetadata(log.Metadata), maxMetadataWidth),
+		}, "\t"))
+	}
+
+	if err := table.Flush(); err != nil {
+		return fmt.Errorf("failed to write table: %w", err)
+	}
+
+	p.logs = p.logs[:0]
+
+	return nil
+}
+
+// cell は表の 1 セルに収まるよう値を整形する
+// 改行・タブは空白に置き換え、width（0 は無制限）を超える場合は末尾を省略する
+func cell(value string, width int) string {
+	value = strings.NewReplacer("\r", " ", "\n", " ", "\t", " ").Replace(value)
+	if value == "" {
+		return "-"
+	}
+
+	runes := []rune(value)
+	if width > 0 && len(runes) > width {
+		return string(runes[:width-1]) + ellipsis
+	}
+
+	return value
+}
+
+// formatMetadata はメタデータをキー順に key=value の空白区切りで整形する
+func formatMetadata(metadata map[string]string) string {
+	pairs := make([]string, 0, len(metadata))
+	for _, key := range slices.Sorted(maps.Keys(metadata)) {
+		pairs = append(pairs, key+"="+metadata[key])
+	}
+
+	return strings.Join(pairs, " ")
+}
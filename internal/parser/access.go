@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// アクセスログの正規表現（名前付きグループがフィールド名になる）
+// 引用符で囲んだ値は nginx / Apache がエスケープした \" を含められる
+const (
+	// accessLogCommonFields は Common Log Format の項目（host ident authuser [date] "request" status bytes）
+	accessLogCommonFields = `^(?P<remote_addr>\S+) (?P<ident>\S+) (?P<remote_user>\S+) \[(?P<time>[^\]]+)\] ` +
+		`"(?P<request>(?:[^"\\]|\\.)*)" (?P<status>\d{3}) (?P<bytes>\d+|-)`
+
+	// accessLogCommonPattern は Common Log Format の行
+	accessLogCommonPattern = accessLogCommonFields + `\s*$`
+
+	// accessLogCombinedPattern は Combined Log Format（Common Log Format に "referer" "user-agent" を追加した形式）の行
+	// 末尾の数値はリクエストの処理時間（nginx の $request_time など）とみなし、それ以降の項目は無視する
+	accessLogCombinedPattern = accessLogCommonFields + ` "(?P<referer>(?:[^"\\]|\\.)*)" "(?P<user_agent>(?:[^"\\]|\\.)*)"` +
+		`(?: (?P<request_time>\d+(?:\.\d+)?))?(?:\s.*)?$`
+)
+
+// accessLogTimeLayout はアクセスログの時刻の形式（例: 10/Oct/2000:13:55:36 -0700）
+const accessLogTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLogParser は nginx / Apache のアクセスログ（Common Log Format / Combined Log Format）を解析するパーサー
+// ステータスコードからレベル（5xx は ERROR、4xx は WARN、それ以外は INFO）を決め、
+// リクエスト行をメソッド・パス・プロトコルに分解する。値が "-" の項目はフィールドに含めない
+// メッセージのフィールドは設定しないため、既定のマッピングでは行全体がメッセージになる
+type AccessLogParser struct {
+	format string
+	regex  *RegexParser
+}
+
+// newAccessLogParser は format（NameCommon / NameCombined）の AccessLogParser を生成する
+func newAccessLogParser(format, pattern string) *AccessLogParser {
+	return &AccessLogParser{format: format, regex: &RegexParser{pattern: regexp.MustCompile(pattern)}}
+}
+
+// Parse は行を解析し、アクセスログの各項目をフィールドとして返す
+func (p *AccessLogParser) Parse(line string) (Fields, error) {
+	matched, err := p.regex.Parse(line)
+	if err != nil {
+		return nil, fmt.Errorf("%w: expected %s log format", err, p.format)
+	}
+
+	fields := Fields{}
+
+	for key, value := range matched {
+		if value != "-" && key != "ident" && key != "time" && key != "request" {
+			fields[key] = value
+		}
+	}
+
+	if matched["bytes"] == "-" {
+		fields["bytes"] = "0"
+	}
+
+	// 時刻は既定のタイムスタンプのレイアウトで解釈できる RFC 3339 形式に変換する（解釈できない場合はそのまま）
+	fields[TargetTimestamp] = matched["time"]
+	if timestamp, err := time.Parse(accessLogTimeLayout, matched["time"]); err == nil {
+		fields[TargetTimestamp] = timestamp.Format(time.RFC3339Nano)
+	}
+
+	// "GET /path HTTP/1.1" の形式でないリクエスト行（不正なリクエストなど）はそのまま残す
+	if method, rest, ok := strings.Cut(matched["request"], " "); ok {
+		path, protocol, _ := strings.Cut(rest, " ")
+		fields["method"], fields["path"] = method, path
+
+		if protocol != "" {
+			fields["protocol"] = protocol
+		}
+	} else if matched["request"] != "" && matched["request"] != "-" {
+		fields["request"] = matched["request"]
+	}
+
+	status, _ := strconv.Atoi(matched["status"]) // 正規表現で 3 桁の数字であることを検証済み
+	fields[TargetLevel] = statusLevel(status)
+
+	return fields, nil
+}
+
+// statusLevel は HTTP のステータスコードの区分に対応するレベルを返す
+func statusLevel(status int) string {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return string(model.LevelError)
+	case status >= http.StatusBadRequest:
+		return string(model.LevelWarn)
+	default:
+		return string(model.LevelInfo)
+	}
+}
@@ -0,0 +1,186 @@
+package parser_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	"github.com/KeitaShimura/logs-collector-client/internal/parser"
+)
+
+// TestAccessLogParser はアクセスログの各項目がフィールドに分解され、ステータスコードからレベルが決まることを検証する
+func TestAccessLogParser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		parser string
+		line   string
+		want   parser.Fields
+	}{
+		{
+			"common",
+			parser.NameCommon,
+			`127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326`,
+			parser.Fields{
+				"remote_addr": "127.0.0.1",
+				"remote_user": "frank",
+				"timestamp":   "2000-10-10T13:55:36-07:00",
+				"method":      "GET",
+				"path":        "/apache_pb.gif",
+				"protocol":    "HTTP/1.0",
+				"status":      "200",
+				"bytes":       "2326",
+				"level":       "INFO",
+			},
+		},
+		{
+			"combined",
+			parser.NameCombined,
+			`10.0.0.5 - - [02/Jan/2025:03:04:05 +0000] "POST /api/orders?id=1 HTTP/1.1" 502 157 ` +
+				`"https://example.com/cart" "Mozilla/5.0 (X11; Linux x86_64)"`,
+			parser.Fields{
+				"remote_addr": "10.0.0.5",
+				"timestamp":   "2025-01-02T03:04:05Z",
+				"method":      "POST",
+				"path":        "/api/orders?id=1",
+				"protocol":    "HTTP/1.1",
+				"status":      "502",
+				"bytes":       "157",
+				"referer":     "https://example.com/cart",
+				"user_agent":  "Mozilla/5.0 (X11; Linux x86_64)",
+				"level":       "ERROR",
+			},
+		},
+		{
+			"combined with request time and escaped quotes",
+			parser.NameCombined,
+			`10.0.0.5 - - [02/Jan/2025:03:04:05 +0000] "GET /search?q=\"x\" HTTP/2.0" 404 - "-" "curl/8.5.0 \"test\"" 0.012 "upstream"`,
+			parser.Fields{
+				"remote_addr":  "10.0.0.5",
+				"timestamp":    "2025-01-02T03:04:05Z",
+				"method":       "GET",
+				"path":         `/search?q=\"x\"`,
+				"protocol":     "HTTP/2.0",
+				"status":       "404",
+				"bytes":        "0",
+				"user_agent":   `curl/8.5.0 \"test\"`,
+				"request_time": "0.012",
+				"level":        "WARN",
+			},
+		},
+		{
+			"malformed request",
+			parser.NameCommon,
+			`10.0.0.5 - - [02/Jan/2025:03:04:05 +0000] "\x16\x03\x01" 400 0`,
+			parser.Fields{
+				"remote_addr": "10.0.0.5",
+				"timestamp":   "2025-01-02T03:04:05Z",
+				"request":     `\x16\x03\x01`,
+				"status":      "400",
+				"bytes":       "0",
+				"level":       "WARN",
+			},
+		},
+		{
+			"empty request",
+			parser.NameCombined,
+			`10.0.0.5 - - [02/Jan/2025:03:04:05 +0000] "-" 408 0 "-" "-"`,
+			parser.Fields{
+				"remote_addr": "10.0.0.5",
+				"timestamp":   "2025-01-02T03:04:05Z",
+				"status":      "408",
+				"bytes":       "0",
+				"level":       "WARN",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := parser.New(test.parser, "")
+			require.NoError(t, err)
+
+			fields, err := p.Parse(test.line)
+			require.NoError(t, err)
+			require.Equal(t, test.want, fields)
+		})
+	}
+}
+
+// TestAccessLogParser_ToLog は既定のマッピングでアクセスログが行全体をメッセージとするログに変換されることを検証する
+func TestAccessLogParser_ToLog(t *testing.T) {
+	t.Parallel()
+
+	line := `10.0.0.5 - alice [02/Jan/2025:03:04:05 +0900] "DELETE /items/1 HTTP/1.1" 503 19 "-" "k6/0.49" 1.250`
+
+	p, err := parser.New(parser.NameCombined, "")
+	require.NoError(t, err)
+
+	fields, err := p.Parse(line)
+	require.NoError(t, err)
+
+	log := (&parser.Mapping{}).ToLog(fields, line, nil) //nolint:exhaustruct // ゼロ値は既定のマッピングを使用する
+	require.Equal(t, line, log.Message)
+	require.Equal(t, model.LevelError, log.Level)
+	require.True(t, time.Date(2025, 1, 1, 18, 4, 5, 0, time.UTC).Equal(log.Timestamp), log.Timestamp)
+	require.Equal(t, "DELETE", log.Metadata["method"])
+	require.Equal(t, "1.250", log.Metadata["request_time"])
+	require.Equal(t, "alice", log.Metadata["remote_user"])
+}
+
+// TestAccessLogParser_NoMatch は形式に合わない行がエラーになることを検証する
+func TestAccessLogParser_NoMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		parser string
+		line   string
+	}{
+		{parser.NameCommon, "plain text"},
+		{parser.NameCommon, `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 200 2326 "-" "curl/8.5.0"`},
+		{parser.NameCombined, `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" 200 2326`},
+		{parser.NameCombined, `127.0.0.1 - - [10/Oct/2000:13:55:36 -0700] "GET / HTTP/1.0" OK 2326 "-" "-"`},
+	}
+
+	for _, test := range tests {
+		p, err := parser.New(test.parser, "")
+		require.NoError(t, err)
+
+		_, err = p.Parse(test.line)
+		require.ErrorIs(t, err, parser.ErrNoMatch, test.line)
+	}
+}
+
+// TestAccessLogParser_CustomLayouts は TIMESTAMP_LAYOUTS に RFC 3339 形式を含まない場合も、
+// アクセスログや syslog のパーサーが変換した時刻を解釈することを検証する
+func TestAccessLogParser_CustomLayouts(t *testing.T) {
+	t.Parallel()
+
+	layouts := []string{"2006/01/02 15:04:05"}
+
+	tests := []struct {
+		parser string
+		line   string
+	}{
+		{parser.NameCommon, `10.0.0.5 - - [02/Jan/2025:03:04:05 +0900] "GET / HTTP/1.1" 200 5`},
+		{parser.NameCombined, `10.0.0.5 - - [02/Jan/2025:03:04:05 +0900] "GET / HTTP/1.1" 200 5 "-" "curl/8.5.0"`},
+		{parser.NameSyslog, "<14>Jan  2 03:04:05 web01 nginx: started"},
+	}
+
+	for _, test := range tests {
+		p, err := parser.New(test.parser, "")
+		require.NoError(t, err)
+
+		fields, err := p.Parse(test.line)
+		require.NoError(t, err)
+
+		log := (&parser.Mapping{}).ToLog(fields, test.line, layouts) //nolint:exhaustruct // ゼロ値は既定のマッピングを使用する
+		require.NotContains(t, log.Metadata, parser.ParseErrorKey, test.parser)
+		require.Equal(t, "01-02 03:04:05", log.Timestamp.Format("01-02 15:04:05"), test.parser)
+	}
+}
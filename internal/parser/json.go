@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// metadataKey は、JSON の行でメタデータとして扱うオブジェクトのキー（model.Log の JSON 表現と同じ）
+const metadataKey = "metadata"
+
+// ErrNotJSONObject は、行が JSON オブジェクトでない場合のエラー
+var ErrNotJSONObject = errors.New("line is not a JSON object")
+
+// JSONParser は JSON オブジェクトの行を解析するパーサー
+// ネストしたオブジェクトは "親.子" のキーに展開し、配列は JSON 文字列、null は無視する
+// トップレベルの "metadata" オブジェクトは展開せずにそのままのキーで取り込む
+type JSONParser struct{}
+
+// Parse は JSON オブジェクトの行をフィールドに分解する
+func (p *JSONParser) Parse(line string) (Fields, error) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(line)))
+	decoder.UseNumber()
+
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil || object == nil {
+		return nil, ErrNotJSONObject
+	}
+
+	if decoder.More() {
+		return nil, fmt.Errorf("%w: unexpected data after object", ErrNotJSONObject)
+	}
+
+	fields := Fields{}
+
+	for key, value := range object {
+		if nested, ok := value.(map[string]any); ok && key == metadataKey {
+			flatten(fields, "", nested)
+
+			continue
+		}
+
+		flatten(fields, key, value)
+	}
+
+	return fields, nil
+}
+
+// flatten は JSON の値を文字列に変換して fields に追加する
+func flatten(fields Fields, key string, value any) {
+	switch typed := value.(type) {
+	case nil:
+	case string:
+		fields[key] = typed
+	case map[string]any:
+		for child, nested := range typed {
+			if key != "" {
+				child = key + "." + child
+			}
+
+			flatten(fields, child, nested)
+		}
+	case []any:
+		encoded, err := json.Marshal(typed)
+		if err == nil {
+			fields[key] = string(encoded)
+		}
+	default:
+		// json.Number / bool は文字列表現をそのまま使用する
+		fields[key] = fmt.Sprint(typed)
+	}
+}
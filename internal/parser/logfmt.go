@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidLogfmt は、行を logfmt として解析できない場合のエラー
+var ErrInvalidLogfmt = errors.New("invalid logfmt")
+
+// LogfmtParser は key=value を空白で区切って並べた logfmt 形式の行を解析するパーサー
+// 値はダブルクォートで囲むことができ（エスケープは Go の文字列リテラルと同じ）、値のないキーは空文字列とする
+type LogfmtParser struct{}
+
+// Parse は logfmt 形式の行をフィールドに分解する
+// key=value の組が 1 つもない行はエラーとする
+func (p *LogfmtParser) Parse(line string) (Fields, error) {
+	fields := Fields{}
+	pairs := 0
+	rest := line
+
+	for {
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			break
+		}
+
+		end := strings.IndexAny(rest, "= \t")
+		if end == -1 {
+			end = len(rest)
+		}
+
+		key := rest[:end]
+		if key == "" || strings.ContainsRune(key, '"') {
+			return nil, fmt.Errorf("%w: invalid key at %q", ErrInvalidLogfmt, truncate(rest))
+		}
+
+		rest = rest[end:]
+
+		if !strings.HasPrefix(rest, "=") {
+			fields[key] = ""
+
+			continue
+		}
+
+		value, remaining, err := logfmtValue(rest[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		fields[key] = value
+		rest = remaining
+		pairs++
+	}
+
+	if pairs == 0 {
+		return nil, fmt.Errorf("%w: no key=value pairs", ErrInvalidLogfmt)
+	}
+
+	return fields, nil
+}
+
+// logfmtValue は rest の先頭の値を読み取り、値と残りの文字列を返す
+func logfmtValue(rest string) (string, string, error) {
+	if !strings.HasPrefix(rest, `"`) {
+		end := strings.IndexAny(rest, " \t")
+		if end == -1 {
+			return rest, "", nil
+		}
+
+		return rest[:end], rest[end:], nil
+	}
+
+	// 閉じるクォートを探す（バックスラッシュの直後の文字は読み飛ばす）
+	for i := 1; i < len(rest); i++ {
+		switch rest[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(rest[:i+1])
+			if err != nil {
+				return "", "", fmt.Errorf("%w: invalid quoted value %q", ErrInvalidLogfmt, truncate(rest[:i+1]))
+			}
+
+			return value, rest[i+1:], nil
+		}
+	}
+
+	return "", "", fmt.Errorf("%w: unterminated quoted value %q", ErrInvalidLogfmt, truncate(rest))
+}
+
+// truncate はエラーメッセージに含める文字列を先頭の一定の長さに切り詰める
+func truncate(value string) string {
+	const maxLength = 32
+
+	if len(value) <= maxLength {
+		return value
+	}
+
+	return value[:maxLength] + "..."
+}
@@ -0,0 +1,53 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/parser"
+)
+
+// TestLogfmtParser は logfmt の行がフィールドに分解されることを検証する
+func TestLogfmtParser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		line string
+		want parser.Fields
+	}{
+		{"simple", "level=info msg=started port=8080", parser.Fields{"level": "info", "msg": "started", "port": "8080"}},
+		{"quoted", `msg="user \"alice\" logged in" path=/login`, parser.Fields{"msg": `user "alice" logged in`, "path": "/login"}},
+		{"empty and bare", `a= b c="" d=1`, parser.Fields{"a": "", "b": "", "c": "", "d": "1"}},
+		{"extra spaces and tabs", "  a=1\t\tb=2  ", parser.Fields{"a": "1", "b": "2"}},
+		{"duplicate key", "a=1 a=2", parser.Fields{"a": "2"}},
+		{"unicode", `msg="ログイン成功" user=太郎`, parser.Fields{"msg": "ログイン成功", "user": "太郎"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			fields, err := (&parser.LogfmtParser{}).Parse(test.line)
+			require.NoError(t, err)
+			require.Equal(t, test.want, fields)
+		})
+	}
+}
+
+// TestLogfmtParser_Invalid は logfmt として解析できない行がエラーになることを検証する
+func TestLogfmtParser_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, line := range []string{
+		"just some words",
+		`msg="unterminated`,
+		`=value`,
+		`"quoted"=key`,
+		`msg="bad escape \q"`,
+	} {
+		_, err := (&parser.LogfmtParser{}).Parse(line)
+		require.ErrorIs(t, err, parser.ErrInvalidLogfmt, line)
+	}
+}
@@ -0,0 +1,192 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// マッピングの対象（Mapping.Set に指定する値）
+const (
+	TargetLevel     = "level"
+	TargetMessage   = "message"
+	TargetTimestamp = "timestamp"
+	TargetTraceID   = "trace_id"
+	TargetService   = "service"
+)
+
+// ErrInvalidMapping は、フィールドのマッピングの指定が不正な場合のエラー
+var ErrInvalidMapping = errors.New("field mapping must be in target=key[,key...] form")
+
+// Mapping はフィールドのキーと model.Log の項目の対応を保持する構造体
+// 各項目には候補となるキーを優先順に指定し、値が空でない最初のキーを使用する
+// 空の項目は DefaultMapping の候補を使用する
+type Mapping struct {
+	Level     []string
+	Message   []string
+	Timestamp []string
+	TraceID   []string
+	Service   []string
+}
+
+// DefaultMapping は既定のフィールドのマッピングを返す
+func DefaultMapping() Mapping {
+	return Mapping{
+		Level:     []string{"level", "lvl", "severity"},
+		Message:   []string{"message", "msg"},
+		Timestamp: []string{"timestamp", "time", "ts", "@timestamp"},
+		TraceID:   []string{"traceId", "trace_id", "trace"},
+		Service:   []string{"service", "app"},
+	}
+}
+
+// String は DefaultMapping から変更した項目を target=key,... の形式で返す（flag.Value の実装）
+func (m *Mapping) String() string {
+	if m == nil {
+		return ""
+	}
+
+	var specs []string
+
+	for _, target := range m.targets() {
+		if len(*target.keys) > 0 {
+			specs = append(specs, target.name+"="+strings.Join(*target.keys, ","))
+		}
+	}
+
+	return strings.Join(specs, " ")
+}
+
+// Set は target=key[,key...] の形式で項目の候補のキーを置き換える（flag.Value の実装）
+func (m *Mapping) Set(spec string) error {
+	name, value, ok := strings.Cut(spec, "=")
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidMapping, spec)
+	}
+
+	var keys []string
+
+	for key := range strings.SplitSeq(value, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidMapping, spec)
+	}
+
+	for _, target := range m.targets() {
+		if target.name == strings.TrimSpace(name) {
+			*target.keys = keys
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: unknown target %q (must be %s, %s, %s, %s or %s)",
+		ErrInvalidMapping, name, TargetLevel, TargetMessage, TargetTimestamp, TargetTraceID, TargetService)
+}
+
+// mappingTarget はマッピングの対象の名前と候補のキーの組
+type mappingTarget struct {
+	name string
+	keys *[]string
+}
+
+// targets はマッピングの対象を一覧で返す
+func (m *Mapping) targets() []mappingTarget {
+	return []mappingTarget{
+		{name: TargetLevel, keys: &m.Level},
+		{name: TargetMessage, keys: &m.Message},
+		{name: TargetTimestamp, keys: &m.Timestamp},
+		{name: TargetTraceID, keys: &m.TraceID},
+		{name: TargetService, keys: &m.Service},
+	}
+}
+
+// ToLog はフィールドを model.Log に変換する
+//   - マッピングした項目以外のフィールドは Metadata に設定する
+//   - メッセージのフィールドがない場合は行全体をメッセージとする
+//   - timestamp（layouts で解釈）や level を変換できない場合は元の値を Metadata に残し、ParseErrorKey に理由を設定する
+//   - timestamp は layouts によらず RFC 3339 形式を先に試す（syslog やアクセスログのパーサーは時刻を RFC 3339 形式に変換して渡す）
+func (m *Mapping) ToLog(fields Fields, line string, layouts []string) *model.Log {
+	defaults := DefaultMapping()
+	metadata := maps.Clone(fields)
+	if metadata == nil {
+		metadata = Fields{}
+	}
+
+	var problems []string
+
+	take := func(keys, fallback []string) (string, string) {
+		if len(keys) == 0 {
+			keys = fallback
+		}
+
+		for _, key := range keys {
+			if value := fields[key]; value != "" {
+				delete(metadata, key)
+
+				return key, value
+			}
+		}
+
+		return "", ""
+	}
+
+	log := &model.Log{
+		ID:        "",
+		TraceID:   "",
+		Timestamp: time.Time{},
+		Level:     "",
+		Service:   "",
+		Message:   line,
+		Metadata:  nil,
+	}
+
+	if _, message := take(m.Message, defaults.Message); message != "" {
+		log.Message = message
+	}
+
+	_, log.TraceID = take(m.TraceID, defaults.TraceID)
+	_, log.Service = take(m.Service, defaults.Service)
+
+	if key, value := take(m.Level, defaults.Level); value != "" {
+		level, err := model.ParseLevel(value)
+		if err != nil {
+			metadata[key] = value
+			problems = append(problems, err.Error())
+		}
+
+		log.Level = level
+	}
+
+	if key, value := take(m.Timestamp, defaults.Timestamp); value != "" {
+		timestamp, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(value))
+		if err != nil {
+			timestamp, err = model.ParseTimestamp(value, layouts)
+		}
+
+		if err != nil {
+			metadata[key] = value
+			problems = append(problems, err.Error())
+		}
+
+		log.Timestamp = timestamp
+	}
+
+	if len(problems) > 0 {
+		metadata[ParseErrorKey] = strings.Join(problems, "; ")
+	}
+
+	if len(metadata) > 0 {
+		log.Metadata = metadata
+	}
+
+	return log
+}
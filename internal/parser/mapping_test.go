@@ -0,0 +1,80 @@
+package parser_test
+
+import (
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	"github.com/KeitaShimura/logs-collector-client/internal/parser"
+)
+
+// TestMapping_ToLog は既定のマッピングでフィールドが各項目とメタデータに振り分けられることを検証する
+func TestMapping_ToLog(t *testing.T) {
+	t.Parallel()
+
+	fields := parser.Fields{
+		"time":     "2025-01-02T03:04:05.5Z",
+		"severity": "warning",
+		"msg":      "slow query",
+		"trace_id": "abc",
+		"app":      "db",
+		"duration": "1.5s",
+	}
+
+	log := (&parser.Mapping{}).ToLog(fields, "raw line", nil) //nolint:exhaustruct // ゼロ値は既定のマッピングを使用する
+
+	require.Equal(t, time.Date(2025, 1, 2, 3, 4, 5, 500000000, time.UTC), log.Timestamp)
+	require.Equal(t, model.LevelWarn, log.Level)
+	require.Equal(t, "slow query", log.Message)
+	require.Equal(t, "abc", log.TraceID)
+	require.Equal(t, "db", log.Service)
+	require.Equal(t, map[string]string{"duration": "1.5s"}, log.Metadata)
+	require.Len(t, fields, 6) // 入力のフィールドは変更しない
+}
+
+// TestMapping_ToLogProblems は変換できない項目の値をメタデータに残し、理由を付与することを検証する
+func TestMapping_ToLogProblems(t *testing.T) {
+	t.Parallel()
+
+	fields := parser.Fields{"timestamp": "yesterday", "level": "loud", "user": "alice"}
+
+	log := (&parser.Mapping{}).ToLog(fields, "raw line", nil) //nolint:exhaustruct // ゼロ値は既定のマッピングを使用する
+
+	require.Equal(t, "raw line", log.Message) // メッセージのフィールドがない場合は行全体
+	require.True(t, log.Timestamp.IsZero())
+	require.Empty(t, log.Level)
+	require.Equal(t, "yesterday", log.Metadata["timestamp"])
+	require.Equal(t, "loud", log.Metadata["level"])
+	require.Equal(t, "alice", log.Metadata["user"])
+	require.Contains(t, log.Metadata[parser.ParseErrorKey], "unknown level")
+	require.Contains(t, log.Metadata[parser.ParseErrorKey], "invalid timestamp")
+}
+
+// TestMapping_Set は --field の指定で候補のキーが置き換わり、不正な指定がエラーになることを検証する
+func TestMapping_Set(t *testing.T) {
+	t.Parallel()
+
+	var mapping parser.Mapping
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.Var(&mapping, "field", "")
+
+	require.NoError(t, flags.Parse([]string{"--field", "message=log, text", "--field", "level=sev"}))
+	require.Equal(t, []string{"log", "text"}, mapping.Message)
+	require.Equal(t, []string{"sev"}, mapping.Level)
+	require.Equal(t, "level=sev message=log,text", mapping.String())
+
+	log := mapping.ToLog(parser.Fields{"text": "hello", "message": "ignored", "sev": "ERROR"}, "raw", nil)
+	require.Equal(t, "hello", log.Message)
+	require.Equal(t, model.LevelError, log.Level)
+	require.Equal(t, map[string]string{"message": "ignored"}, log.Metadata)
+
+	for _, spec := range []string{"message", "message=", "host=hostname"} {
+		require.ErrorIs(t, mapping.Set(spec), parser.ErrInvalidMapping, spec)
+	}
+}
@@ -0,0 +1,82 @@
+// Package parser は、入力の 1 行を解析してフィールドに分解する行パーサーを提供する
+// 解析したフィールドは Mapping によって model.Log の各項目とメタデータに振り分けられる
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// パーサー名（New に指定する値）
+const (
+	NameJSON   = "json"
+	NameLogfmt = "logfmt"
+	NameRegex  = "regex"
+	NameSyslog = "syslog"
+
+	// Web サーバーのアクセスログのプリセット
+	NameCommon   = "common"   // Common Log Format
+	NameCombined = "combined" // Combined Log Format（nginx の既定の形式）
+)
+
+// ParseErrorKey は、解析できなかった行や項目の変換に失敗した行に付与するメタデータのキー
+const ParseErrorKey = "parse_error"
+
+// パーサーに関するエラー
+var (
+	ErrUnknownParser     = errors.New("unknown parser")
+	ErrMissingPattern    = errors.New("regex parser requires a pattern")
+	ErrUnexpectedPattern = errors.New("pattern is only used by the regex parser")
+)
+
+// Fields は行を解析して得られたキーと値
+type Fields map[string]string
+
+// Parser は 1 行を解析してフィールドに分解するインターフェース
+// 解析できない行はエラーを返す
+type Parser interface {
+	Parse(line string) (Fields, error)
+}
+
+// インターフェースを満たしていることをコンパイル時に検証する
+var (
+	_ Parser = (*JSONParser)(nil)
+	_ Parser = (*LogfmtParser)(nil)
+	_ Parser = (*RegexParser)(nil)
+	_ Parser = (*SyslogParser)(nil)
+	_ Parser = (*AccessLogParser)(nil)
+)
+
+// New は名前に対応するパーサーを生成する
+// pattern は regex パーサーの正規表現（名前付きグループがフィールド名になる）で、それ以外のパーサーには指定できない
+//
+//nolint:ireturn // パーサーの種類を呼び出し側から隠蔽するためインターフェースを返す
+func New(name, pattern string) (Parser, error) {
+	if name != NameRegex && pattern != "" {
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedPattern, name)
+	}
+
+	switch name {
+	case NameJSON:
+		return &JSONParser{}, nil
+	case NameLogfmt:
+		return &LogfmtParser{}, nil
+	case NameRegex:
+		if pattern == "" {
+			return nil, ErrMissingPattern
+		}
+
+		return NewRegexParser(pattern)
+	case NameSyslog:
+		return &SyslogParser{Now: nil, Location: nil}, nil
+	case NameCommon:
+		return newAccessLogParser(NameCommon, accessLogCommonPattern), nil
+	case NameCombined:
+		return newAccessLogParser(NameCombined, accessLogCombinedPattern), nil
+	default:
+		names := []string{NameJSON, NameLogfmt, NameRegex, NameSyslog, NameCommon, NameCombined}
+
+		return nil, fmt.Errorf("%w: %q (must be one of %s)", ErrUnknownParser, name, strings.Join(names, ", "))
+	}
+}
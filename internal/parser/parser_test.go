@@ -0,0 +1,93 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/parser"
+)
+
+// TestNew は名前とパターンの組み合わせに応じてパーサーを生成・拒否することを検証する
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		parser  string
+		pattern string
+		wantErr error
+	}{
+		{"json", parser.NameJSON, "", nil},
+		{"logfmt", parser.NameLogfmt, "", nil},
+		{"regex", parser.NameRegex, `(?P<message>.*)`, nil},
+		{"syslog", parser.NameSyslog, "", nil},
+		{"common", parser.NameCommon, "", nil},
+		{"combined", parser.NameCombined, "", nil},
+		{"pattern with combined", parser.NameCombined, `(?P<message>.*)`, parser.ErrUnexpectedPattern},
+		{"regex without pattern", parser.NameRegex, "", parser.ErrMissingPattern},
+		{"regex without named groups", parser.NameRegex, `(.*)`, parser.ErrInvalidPattern},
+		{"invalid regex", parser.NameRegex, `(?P<message>`, parser.ErrInvalidPattern},
+		{"pattern with json", parser.NameJSON, `(?P<message>.*)`, parser.ErrUnexpectedPattern},
+		{"unknown", "xml", "", parser.ErrUnknownParser},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, err := parser.New(test.parser, test.pattern)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, p)
+		})
+	}
+}
+
+// TestJSONParser は JSON の行がネストを展開したフィールドに分解されることを検証する
+func TestJSONParser(t *testing.T) {
+	t.Parallel()
+
+	line := `{"msg":"login","status":200,"ok":true,"latency":0.25,"user":{"id":"u1","roles":["admin"]},"metadata":{"env":"prod"},"note":null}`
+
+	fields, err := (&parser.JSONParser{}).Parse(line)
+	require.NoError(t, err)
+	require.Equal(t, parser.Fields{
+		"msg":        "login",
+		"status":     "200",
+		"ok":         "true",
+		"latency":    "0.25",
+		"user.id":    "u1",
+		"user.roles": `["admin"]`,
+		"env":        "prod",
+	}, fields)
+
+	for _, invalid := range []string{"plain text", `["array"]`, `{"a":1} trailing`, `{"a":`} {
+		_, err := (&parser.JSONParser{}).Parse(invalid)
+		require.ErrorIs(t, err, parser.ErrNotJSONObject, invalid)
+	}
+}
+
+// TestRegexParser は名前付きグループの値がフィールドになり、マッチしない行がエラーになることを検証する
+func TestRegexParser(t *testing.T) {
+	t.Parallel()
+
+	p, err := parser.NewRegexParser(`^(?P<time>\S+) \[(?<level>\w+)\](?: (?P<trace>[0-9a-f]+):)? (?P<message>.*)$`)
+	require.NoError(t, err)
+
+	fields, err := p.Parse("2025-01-02T03:04:05Z [WARN] 0af7 : disk almost full")
+	require.NoError(t, err)
+	require.Equal(t, parser.Fields{"time": "2025-01-02T03:04:05Z", "level": "WARN", "message": "0af7 : disk almost full"}, fields)
+
+	fields, err = p.Parse("2025-01-02T03:04:05Z [INFO] 0af7: started")
+	require.NoError(t, err)
+	require.Equal(t, "0af7", fields["trace"])
+
+	_, err = p.Parse("not a structured line")
+	require.ErrorIs(t, err, parser.ErrNoMatch)
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// 正規表現のパーサーに関するエラー
+var (
+	ErrInvalidPattern = errors.New("invalid regex pattern")
+	ErrNoMatch        = errors.New("line does not match pattern")
+)
+
+// RegexParser は正規表現の名前付きグループ（(?P<name>...) / (?<name>...)）をフィールドとして取り出すパーサー
+// マッチしなかった省略可能なグループは空文字列ではなくフィールドなしとして扱う
+type RegexParser struct {
+	pattern *regexp.Regexp
+}
+
+// NewRegexParser は正規表現をコンパイルして RegexParser を生成する
+// 名前付きグループを 1 つも含まない正規表現はエラーとする
+func NewRegexParser(pattern string) (*RegexParser, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPattern, err)
+	}
+
+	for _, name := range compiled.SubexpNames() {
+		if name != "" {
+			return &RegexParser{pattern: compiled}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %q has no named groups", ErrInvalidPattern, pattern)
+}
+
+// Parse は行を正規表現に照合し、名前付きグループの値をフィールドとして返す
+func (p *RegexParser) Parse(line string) (Fields, error) {
+	match := p.pattern.FindStringSubmatchIndex(line)
+	if match == nil {
+		return nil, ErrNoMatch
+	}
+
+	fields := Fields{}
+
+	for i, name := range p.pattern.SubexpNames() {
+		start, end := match[2*i], match[2*i+1]
+		if name == "" || start < 0 {
+			continue
+		}
+
+		fields[name] = line[start:end]
+	}
+
+	return fields, nil
+}
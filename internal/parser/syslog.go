@@ -0,0 +1,285 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// syslog のメッセージから取り出すフィールドのキー（既定のマッピングで level / message / timestamp / service に対応する）
+const (
+	SyslogFacilityKey = "facility"
+	SyslogHostnameKey = "hostname"
+	SyslogProcIDKey   = "proc_id"
+	SyslogMsgIDKey    = "msg_id"
+)
+
+// syslog の PRI の最大値（facility 23 × 8 + severity 7）
+const maxSyslogPriority = 191
+
+// ErrInvalidSyslog は、syslog のメッセージとして解析できない場合のエラー
+var ErrInvalidSyslog = errors.New("invalid syslog message")
+
+// syslogFacilities は facility の番号に対応する名前
+var syslogFacilities = [...]string{ //nolint:gochecknoglobals // RFC 5424 で定義された facility の一覧
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+	"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console", "solaris-cron",
+	"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+// syslogSeverities は severity の番号に対応する名前（いずれも model.ParseLevel で解釈できる）
+var syslogSeverities = [...]string{ //nolint:gochecknoglobals // RFC 5424 で定義された severity の一覧
+	"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug",
+}
+
+// SyslogParser は RFC 5424 と RFC 3164（BSD syslog）形式のメッセージを解析するパーサー
+// PRI の直後が "1 " の場合は RFC 5424、それ以外は RFC 3164 として解析する
+// severity を level、APP-NAME（RFC 3164 では TAG）を service、STRUCTURED-DATA の各パラメーターを "SD-ID.名前" のフィールドとする
+type SyslogParser struct {
+	Now      func() time.Time // 年を含まない RFC 3164 のタイムスタンプの年の補完に使用する（nil の場合は time.Now）
+	Location *time.Location   // RFC 3164 のタイムスタンプのタイムゾーン（nil の場合は time.Local）
+}
+
+// Parse はメッセージを解析し、ヘッダーと本文をフィールドとして返す
+func (p *SyslogParser) Parse(line string) (Fields, error) {
+	priority, rest, err := cutPriority(line)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := Fields{
+		SyslogFacilityKey: syslogFacilities[priority/8],
+		TargetLevel:       syslogSeverities[priority%8],
+	}
+
+	if body, ok := strings.CutPrefix(rest, "1 "); ok {
+		if err := parseRFC5424(body, fields); err != nil {
+			return nil, err
+		}
+
+		return fields, nil
+	}
+
+	p.parseRFC3164(rest, fields)
+
+	return fields, nil
+}
+
+// cutPriority は先頭の PRI（"<0>" 〜 "<191>"）を解釈し、残りの部分とともに返す
+func cutPriority(line string) (int, string, error) {
+	inner, ok := strings.CutPrefix(line, "<")
+	if !ok {
+		return 0, "", fmt.Errorf("%w: missing PRI", ErrInvalidSyslog)
+	}
+
+	digits, rest, ok := strings.Cut(inner, ">")
+	if !ok || digits == "" || len(digits) > 3 {
+		return 0, "", fmt.Errorf("%w: malformed PRI", ErrInvalidSyslog)
+	}
+
+	priority, err := strconv.Atoi(digits)
+	if err != nil || priority < 0 || priority > maxSyslogPriority {
+		return 0, "", fmt.Errorf("%w: PRI %q out of range", ErrInvalidSyslog, digits)
+	}
+
+	return priority, rest, nil
+}
+
+// parseRFC5424 は VERSION 以降の RFC 5424 のヘッダー・STRUCTURED-DATA・本文を解析する
+// "-"（NILVALUE）の項目はフィールドに含めない
+func parseRFC5424(body string, fields Fields) error {
+	keys := [...]string{TargetTimestamp, SyslogHostnameKey, TargetService, SyslogProcIDKey, SyslogMsgIDKey}
+
+	for _, key := range keys {
+		value, rest, ok := strings.Cut(body, " ")
+		if !ok || value == "" {
+			return fmt.Errorf("%w: truncated RFC 5424 header", ErrInvalidSyslog)
+		}
+
+		if value != "-" {
+			fields[key] = value
+		}
+
+		body = rest
+	}
+
+	body, err := parseStructuredData(body, fields)
+	if err != nil {
+		return err
+	}
+
+	if body != "" {
+		message, ok := strings.CutPrefix(body, " ")
+		if !ok {
+			return fmt.Errorf("%w: missing space before MSG", ErrInvalidSyslog)
+		}
+
+		fields[TargetMessage] = strings.TrimPrefix(message, "\ufeff")
+	}
+
+	return nil
+}
+
+// parseStructuredData は STRUCTURED-DATA（"-" または "[SD-ID 名前="値" ...]" の並び）を解析し、残りの部分を返す
+// 値の中の \" \\ \] はエスケープを解除する
+func parseStructuredData(body string, fields Fields) (string, error) {
+	if rest, ok := strings.CutPrefix(body, "-"); ok {
+		return rest, nil
+	}
+
+	if !strings.HasPrefix(body, "[") {
+		return "", fmt.Errorf("%w: malformed STRUCTURED-DATA", ErrInvalidSyslog)
+	}
+
+	for strings.HasPrefix(body, "[") {
+		end := strings.IndexAny(body, " ]")
+		if end <= 1 {
+			return "", fmt.Errorf("%w: missing SD-ID", ErrInvalidSyslog)
+		}
+
+		id := body[1:end]
+		body = body[end:]
+
+		for strings.HasPrefix(body, " ") {
+			name, rest, ok := strings.Cut(body[1:], `="`)
+			if !ok || name == "" || strings.ContainsAny(name, ` ]"`) {
+				return "", fmt.Errorf("%w: malformed SD-PARAM in [%s]", ErrInvalidSyslog, id)
+			}
+
+			value, rest, err := cutParamValue(rest)
+			if err != nil {
+				return "", fmt.Errorf("%w in [%s]", err, id)
+			}
+
+			fields[id+"."+name] = value
+			body = rest
+		}
+
+		rest, ok := strings.CutPrefix(body, "]")
+		if !ok {
+			return "", fmt.Errorf("%w: unterminated [%s]", ErrInvalidSyslog, id)
+		}
+
+		body = rest
+	}
+
+	return body, nil
+}
+
+// cutParamValue は閉じる '"' までの PARAM-VALUE のエスケープを解除し、'"' の後の部分とともに返す
+func cutParamValue(body string) (string, string, error) {
+	var value strings.Builder
+
+	for i := 0; i < len(body); i++ {
+		switch body[i] {
+		case '"':
+			return value.String(), body[i+1:], nil
+		case '\\':
+			// \" \\ \] 以外のバックスラッシュはそのまま残す
+			if i+1 < len(body) && strings.IndexByte(`"\]`, body[i+1]) >= 0 {
+				i++
+			}
+		}
+
+		value.WriteByte(body[i])
+	}
+
+	return "", "", fmt.Errorf("%w: unterminated PARAM-VALUE", ErrInvalidSyslog)
+}
+
+// parseRFC3164 は PRI 以降の RFC 3164 の TIMESTAMP・HOSTNAME・TAG・本文を解析する
+// RFC 3164 の形式は実装による揺れが大きいため、解釈できない部分は本文として扱いエラーにしない
+//   - TIMESTAMP は "Jan _2 15:04:05" または RFC 3339 形式を受け付け、ない場合は HOSTNAME もないとみなす
+//   - TIMESTAMP の直後が TAG（"app:" / "app[pid]:"）の場合は HOSTNAME を省略したとみなす
+func (p *SyslogParser) parseRFC3164(rest string, fields Fields) {
+	timestamp, rest, ok := p.cutTimestamp(rest)
+	if ok {
+		fields[TargetTimestamp] = timestamp.Format(time.RFC3339Nano)
+
+		if _, _, _, tagged := cutTag(rest); !tagged {
+			if hostname, after, found := strings.Cut(rest, " "); found && hostname != "" {
+				fields[SyslogHostnameKey] = hostname
+				rest = after
+			}
+		}
+	}
+
+	if app, pid, message, tagged := cutTag(rest); tagged {
+		fields[TargetService] = app
+		rest = message
+
+		if pid != "" {
+			fields[SyslogProcIDKey] = pid
+		}
+	}
+
+	fields[TargetMessage] = rest
+}
+
+// cutTimestamp は先頭の RFC 3164 の TIMESTAMP を解釈し、直後の空白を除いた残りの部分とともに返す
+// 年を含まない場合は現在の年を補い、現在時刻より 1 日以上先になる場合は前年とみなす（年をまたいだ受信）
+func (p *SyslogParser) cutTimestamp(rest string) (time.Time, string, bool) {
+	location := p.Location
+	if location == nil {
+		location = time.Local
+	}
+
+	// "Jan _2 15:04:05"（日が 1 桁の場合は空白で 2 桁に揃える）
+	if len(rest) > len(time.Stamp) && rest[len(time.Stamp)] == ' ' {
+		if parsed, err := time.ParseInLocation(time.Stamp, rest[:len(time.Stamp)], location); err == nil {
+			now := time.Now()
+			if p.Now != nil {
+				now = p.Now()
+			}
+
+			now = now.In(location)
+
+			timestamp := time.Date(now.Year(), parsed.Month(), parsed.Day(),
+				parsed.Hour(), parsed.Minute(), parsed.Second(), 0, location)
+			if timestamp.After(now.Add(24 * time.Hour)) {
+				timestamp = timestamp.AddDate(-1, 0, 0)
+			}
+
+			return timestamp, rest[len(time.Stamp)+1:], true
+		}
+	}
+
+	// rsyslog などが送信する RFC 3339 形式
+	if value, after, found := strings.Cut(rest, " "); found {
+		if parsed, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return parsed, after, true
+		}
+	}
+
+	return time.Time{}, rest, false
+}
+
+// cutTag は先頭の TAG（"app:" または "app[pid]:"）を取り出し、APP・PID・直後の空白を除いた本文を返す
+func cutTag(rest string) (string, string, string, bool) {
+	end := strings.IndexAny(rest, " [:")
+	if end <= 0 {
+		return "", "", "", false
+	}
+
+	app, after := rest[:end], rest[end:]
+
+	var pid string
+
+	if inner, ok := strings.CutPrefix(after, "["); ok {
+		value, remaining, found := strings.Cut(inner, "]")
+		if !found || strings.Contains(value, " ") {
+			return "", "", "", false
+		}
+
+		pid, after = value, remaining
+	}
+
+	message, ok := strings.CutPrefix(after, ":")
+	if !ok {
+		return "", "", "", false
+	}
+
+	return app, pid, strings.TrimPrefix(message, " "), true
+}
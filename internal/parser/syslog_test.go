@@ -0,0 +1,132 @@
+package parser_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	"github.com/KeitaShimura/logs-collector-client/internal/parser"
+)
+
+// newSyslogParser は現在時刻を固定した UTC の SyslogParser を生成する
+func newSyslogParser(now time.Time) *parser.SyslogParser {
+	return &parser.SyslogParser{Now: func() time.Time { return now }, Location: time.UTC}
+}
+
+// TestSyslogParser_RFC5424 は RFC 5424 のヘッダー・STRUCTURED-DATA・本文がフィールドに分解されることを検証する
+func TestSyslogParser_RFC5424(t *testing.T) {
+	t.Parallel()
+
+	p := newSyslogParser(time.Now())
+
+	fields, err := p.Parse(`<165>1 2025-01-02T03:04:05.123+09:00 web01 billing 4242 CHARGE ` +
+		`[req@32473 id="r-1" path="/pay \"fast\" \] \\ \x"][origin ip="10.0.0.1"] ` + "\ufeffcharge failed")
+	require.NoError(t, err)
+	require.Equal(t, parser.Fields{
+		"facility":       "local4",
+		"level":          "notice",
+		"timestamp":      "2025-01-02T03:04:05.123+09:00",
+		"hostname":       "web01",
+		"service":        "billing",
+		"proc_id":        "4242",
+		"msg_id":         "CHARGE",
+		"req@32473.id":   "r-1",
+		"req@32473.path": `/pay "fast" ] \ \x`,
+		"origin.ip":      "10.0.0.1",
+		"message":        "charge failed",
+	}, fields)
+
+	// NILVALUE の項目はフィールドに含めない
+	fields, err = p.Parse("<11>1 - - - - - -")
+	require.NoError(t, err)
+	require.Equal(t, parser.Fields{"facility": "user", "level": "err"}, fields)
+
+	log := (&parser.Mapping{}).ToLog(fields, "raw", nil) //nolint:exhaustruct // ゼロ値は既定のマッピングを使用する
+	require.Equal(t, model.LevelError, log.Level)
+}
+
+// TestSyslogParser_RFC3164 は RFC 3164 の揺れのある形式から取り出せる項目を取り出すことを検証する
+func TestSyslogParser_RFC3164(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 30, 0, 0, time.UTC)
+	p := newSyslogParser(now)
+
+	tests := []struct {
+		name string
+		line string
+		want parser.Fields
+	}{
+		{
+			"hostname and tag with pid",
+			"<38>Dec 31 23:59:58 gw01 sshd[812]: Accepted publickey for deploy",
+			parser.Fields{
+				"facility": "auth", "level": "info", "timestamp": "2025-12-31T23:59:58Z", // 年をまたいだ受信は前年とみなす
+				"hostname": "gw01", "service": "sshd", "proc_id": "812", "message": "Accepted publickey for deploy",
+			},
+		},
+		{
+			"without hostname",
+			"<14>Jan  1 00:29:59 cron: job started",
+			parser.Fields{"facility": "user", "level": "info", "timestamp": "2026-01-01T00:29:59Z", "service": "cron", "message": "job started"},
+		},
+		{
+			"without tag",
+			"<12>Jan  1 00:00:01 switch7 link down on port 3",
+			parser.Fields{"facility": "user", "level": "warning", "timestamp": "2026-01-01T00:00:01Z", "hostname": "switch7", "message": "link down on port 3"},
+		},
+		{
+			"rfc3339 timestamp",
+			"<30>2026-01-01T00:10:00.5+09:00 host app[7]: ready",
+			parser.Fields{
+				"facility": "daemon", "level": "info", "timestamp": "2026-01-01T00:10:00.5+09:00",
+				"hostname": "host", "service": "app", "proc_id": "7", "message": "ready",
+			},
+		},
+		{
+			"without timestamp",
+			"<0>kernel: panic",
+			parser.Fields{"facility": "kern", "level": "emerg", "service": "kernel", "message": "panic"},
+		},
+		{
+			"free text",
+			"<191>just some text: with a colon later",
+			parser.Fields{"facility": "local7", "level": "debug", "message": "just some text: with a colon later"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			fields, err := p.Parse(test.line)
+			require.NoError(t, err)
+			require.Equal(t, test.want, fields)
+		})
+	}
+}
+
+// TestSyslogParser_Invalid は PRI や RFC 5424 の構造が不正なメッセージがエラーになることを検証する
+func TestSyslogParser_Invalid(t *testing.T) {
+	t.Parallel()
+
+	p := newSyslogParser(time.Now())
+
+	for _, line := range []string{
+		"plain text",
+		"<>1 - - - - - -",
+		"<192>1 - - - - - -",
+		"<13",
+		"<13>1 - host",
+		"<13>1 - - - - - [id",
+		`<13>1 - - - - - [id key="unterminated]`,
+		`<13>1 - - - - - [id key=unquoted]`,
+		"<13>1 - - - - - x",
+		"<13>1 - - - - - -message without space",
+	} {
+		_, err := p.Parse(line)
+		require.ErrorIs(t, err, parser.ErrInvalidSyslog, line)
+	}
+}
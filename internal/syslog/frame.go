@@ -0,0 +1,84 @@
+package syslog
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// maxFrameLengthDigits は octet counting の長さの最大桁数
+const maxFrameLengthDigits = 10
+
+// フレームに関するエラー
+var (
+	ErrMessageTooLarge = errors.New("syslog message too large")
+	ErrInvalidFrame    = errors.New("invalid syslog frame")
+)
+
+// readFrame は TCP のストリームから RFC 6587 のフレームを 1 件読み取る
+// 先頭が 1-9 の場合は octet counting（"長さ SP メッセージ"）、それ以外は LF までを 1 件とする（non-transparent framing）
+// 接続ごとに送信側が方式を選ぶため、フレームごとに判定する
+func readFrame(reader *bufio.Reader, maxSize int) (string, error) {
+	head, err := reader.Peek(1)
+	if err != nil {
+		return "", fmt.Errorf("failed to read syslog frame: %w", err)
+	}
+
+	if head[0] >= '1' && head[0] <= '9' {
+		return readOctetCounted(reader, maxSize)
+	}
+
+	return readLine(reader, maxSize)
+}
+
+// readOctetCounted は "長さ SP メッセージ" の形式のフレームを読み取る
+func readOctetCounted(reader *bufio.Reader, maxSize int) (string, error) {
+	digits, err := reader.ReadSlice(' ')
+	if err != nil || len(digits) > maxFrameLengthDigits+1 {
+		return "", fmt.Errorf("%w: missing space after MSG-LEN", ErrInvalidFrame)
+	}
+
+	length, err := strconv.Atoi(string(digits[:len(digits)-1]))
+	if err != nil {
+		return "", fmt.Errorf("%w: MSG-LEN %q is not a number", ErrInvalidFrame, digits[:len(digits)-1])
+	}
+
+	if length > maxSize {
+		return "", fmt.Errorf("%w: %d bytes (max %d)", ErrMessageTooLarge, length, maxSize)
+	}
+
+	message := make([]byte, length)
+	if _, err := io.ReadFull(reader, message); err != nil {
+		return "", fmt.Errorf("failed to read syslog frame: %w", err)
+	}
+
+	return string(message), nil
+}
+
+// readLine は LF までを 1 件のフレームとして読み取る
+// 最後のフレームが LF で終わっていない場合は、接続が閉じられた時点までを 1 件とする
+func readLine(reader *bufio.Reader, maxSize int) (string, error) {
+	var line []byte
+
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		line = append(line, chunk...)
+
+		if len(line) > maxSize+1 { // 末尾の LF の分を除いて判定する
+			return "", fmt.Errorf("%w: more than %d bytes without a line feed", ErrMessageTooLarge, maxSize)
+		}
+
+		switch {
+		case err == nil:
+			return string(line), nil
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case errors.Is(err, io.EOF) && len(line) > 0:
+			return string(line), nil
+		default:
+			return "", fmt.Errorf("failed to read syslog frame: %w", err)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+// Package syslog は、UDP・TCP・Unix ドメインソケットで syslog メッセージを受信するリスナーを提供する
+// メッセージの解析は parser.SyslogParser で行う
+package syslog
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/logger"
+)
+
+// 受信方式（Message.Network の値）
+const (
+	NetworkUDP  = "udp"
+	NetworkTCP  = "tcp"
+	NetworkUnix = "unixgram"
+)
+
+// Server の既定値
+const (
+	DefaultMaxMessageSize = 64 * 1024   // 1 件のメッセージの既定の最大バイト数
+	DefaultRetryInterval  = time.Second // TCP のメッセージを再度 Handler に渡すまでの既定の間隔
+)
+
+// Server のエラー
+var (
+	ErrNoListeners = errors.New("no syslog listen address specified")
+	// ErrRejected は、再試行しても処理できないメッセージを示すために Handler が返すエラー（TCP でも再試行せずに破棄する）
+	ErrRejected = errors.New("syslog message rejected")
+)
+
+// Config は Server の設定を保持する構造体
+type Config struct {
+	UDPAddr        string        // UDP で受信するアドレス（空の場合は受信しない）
+	TCPAddr        string        // TCP で受信するアドレス（空の場合は受信しない）
+	UnixPath       string        // データグラムで受信する Unix ドメインソケットのパス（空の場合は受信しない。ストリームのソケットには非対応）
+	MaxMessageSize int           // 1 件のメッセージの最大バイト数（0 以下は DefaultMaxMessageSize）
+	RetryInterval  time.Duration // TCP のメッセージの処理に失敗した場合に再度 Handler に渡すまでの間隔（0 以下は DefaultRetryInterval）
+}
+
+// Enabled は受信するアドレスが 1 つ以上指定されているかを返す
+func (c *Config) Enabled() bool {
+	return c.UDPAddr != "" || c.TCPAddr != "" || c.UnixPath != ""
+}
+
+// Message は受信した 1 件のメッセージとその送信元を保持する構造体
+type Message struct {
+	Network string // 受信方式（NetworkUDP / NetworkTCP / NetworkUnix）
+	Remote  string // 送信元のアドレス（Unix ドメインソケットでは空の場合がある）
+	Data    string // フレームと末尾の改行を除いたメッセージ
+}
+
+// Handler は受信したメッセージを処理するコールバック
+// 同じ TCP 接続のメッセージは順に渡され、処理が終わるまで次のメッセージを読み取らない
+// エラーを返した場合、データグラムのメッセージは破棄する。TCP のメッセージは送信元に待たせるため、
+// 次のメッセージを読み取らずに RetryInterval ごとに再度渡す（ErrRejected の場合と停止時は破棄する）
+type Handler func(ctx context.Context, msg Message) error
+
+// Server は設定されたアドレスで syslog メッセージを受信し、Handler に渡す
+type Server struct {
+	cfg     Config
+	handler Handler
+	logger  logger.Logger
+	udp     net.PacketConn
+	tcp     net.Listener
+	unix    net.PacketConn
+	dropped atomic.Uint64
+}
+
+// Listen は設定されたアドレスで受信を開始した Server を生成する
+// いずれかのアドレスで受信を開始できない場合は、開始済みのソケットを閉じてエラーを返す
+func Listen(cfg Config, handler Handler, logger logger.Logger) (*Server, error) {
+	if !cfg.Enabled() {
+		return nil, ErrNoListeners
+	}
+
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = DefaultMaxMessageSize
+	}
+
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultRetryInterval
+	}
+
+	server := &Server{cfg: cfg, handler: handler, logger: logger, udp: nil, tcp: nil, unix: nil, dropped: atomic.Uint64{}}
+
+	var (
+		lc  net.ListenConfig
+		err error
+	)
+
+	ctx := context.Background()
+
+	if cfg.UDPAddr != "" {
+		if server.udp, err = lc.ListenPacket(ctx, NetworkUDP, cfg.UDPAddr); err != nil {
+			server.close()
+
+			return nil, fmt.Errorf("failed to listen on udp %s: %w", cfg.UDPAddr, err)
+		}
+	}
+
+	if cfg.TCPAddr != "" {
+		if server.tcp, err = lc.Listen(ctx, NetworkTCP, cfg.TCPAddr); err != nil {
+			server.close()
+
+			return nil, fmt.Errorf("failed to listen on tcp %s: %w", cfg.TCPAddr, err)
+		}
+	}
+
+	if cfg.UnixPath != "" {
+		removeStaleSocket(cfg.UnixPath)
+
+		if server.unix, err = lc.ListenPacket(ctx, NetworkUnix, cfg.UnixPath); err != nil {
+			server.close()
+
+			return nil, fmt.Errorf("failed to listen on unix socket %s: %w", cfg.UnixPath, err)
+		}
+	}
+
+	return server, nil
+}
+
+// UDPAddr は UDP で受信しているアドレスを返す（受信していない場合は nil）
+func (s *Server) UDPAddr() net.Addr {
+	if s.udp == nil {
+		return nil
+	}
+
+	return s.udp.LocalAddr()
+}
+
+// TCPAddr は TCP で受信しているアドレスを返す（受信していない場合は nil）
+func (s *Server) TCPAddr() net.Addr {
+	if s.tcp == nil {
+		return nil
+	}
+
+	return s.tcp.Addr()
+}
+
+// Dropped は Handler が処理できずに破棄したメッセージの累計件数を返す
+func (s *Server) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Run はコンテキストがキャンセルされるまでメッセージを受信する
+// キャンセル後はソケットと TCP 接続を閉じ、処理中の Handler の終了を待って戻る
+func (s *Server) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	if s.udp != nil {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			s.readPackets(ctx, NetworkUDP, s.udp)
+		}()
+	}
+
+	if s.unix != nil {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			s.readPackets(ctx, NetworkUnix, s.unix)
+		}()
+	}
+
+	if s.tcp != nil {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			s.accept(ctx, &wg)
+		}()
+	}
+
+	<-ctx.Done()
+	s.close()
+	wg.Wait()
+}
+
+// close はすべてのソケットを閉じ、Unix ドメインソケットのファイルを削除する
+func (s *Server) close() {
+	if s.udp != nil {
+		s.udp.Close()
+	}
+
+	if s.tcp != nil {
+		s.tcp.Close()
+	}
+
+	if s.unix != nil {
+		s.unix.Close()
+		_ = os.Remove(s.cfg.UnixPath) // 削除できない場合は次回の起動時に削除する
+	}
+}
+
+// readPackets はソケットが閉じられるまでデータグラムを受信する（1 データグラムを 1 件のメッセージとする）
+// MaxMessageSize を超える部分は切り捨てる
+func (s *Server) readPackets(ctx context.Context, network string, conn net.PacketConn) {
+	buf := make([]byte, s.cfg.MaxMessageSize)
+
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			s.logger.Warn("failed to read syslog datagram", "network", network, "error", err.Error())
+
+			continue
+		}
+
+		s.dispatch(ctx, network, addr, string(buf[:n]))
+	}
+}
+
+// accept はリスナーが閉じられるまで TCP 接続を受け付け、接続ごとにメッセージを読み取る
+func (s *Server) accept(ctx context.Context, wg *sync.WaitGroup) {
+	for {
+		conn, err := s.tcp.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			s.logger.Warn("failed to accept syslog connection", "error", err.Error())
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			s.serveConn(ctx, conn)
+		}()
+	}
+}
+
+// serveConn は接続が閉じられるまでフレームを読み取る
+// フレームを解釈できない場合やメッセージが大きすぎる場合は接続を閉じる
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	// 停止時に読み取り中の接続を閉じる
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	reader := bufio.NewReader(conn)
+
+	for {
+		frame, err := readFrame(reader, s.cfg.MaxMessageSize)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				s.logger.Warn("closing syslog connection", "remote", conn.RemoteAddr().String(), "error", err.Error())
+			}
+
+			return
+		}
+
+		s.dispatch(ctx, NetworkTCP, conn.RemoteAddr(), frame)
+	}
+}
+
+// dispatch は末尾の改行と NUL を除いたメッセージを Handler に渡す（空のメッセージは無視する）
+// TCP のメッセージは、Handler が処理できるまで RetryInterval ごとに再度渡す
+func (s *Server) dispatch(ctx context.Context, network string, addr net.Addr, data string) {
+	data = strings.TrimRight(data, "\r\n\x00")
+	if strings.TrimSpace(data) == "" {
+		return
+	}
+
+	var remote string
+	if addr != nil {
+		remote = addr.String()
+	}
+
+	msg := Message{Network: network, Remote: remote, Data: data}
+
+	for {
+		err := s.handler(ctx, msg)
+		if err == nil {
+			return
+		}
+
+		if network != NetworkTCP || errors.Is(err, ErrRejected) || !s.wait(ctx) {
+			dropped := s.dropped.Add(1)
+			s.logger.Warn("dropping syslog message", "network", network, "remote", remote, "dropped", dropped, "error", err.Error())
+
+			return
+		}
+
+		s.logger.Warn("failed to handle syslog message, will retry", "network", network, "remote", remote, "error", err.Error())
+	}
+}
+
+// wait は RetryInterval だけ待機する（待機中に停止した場合は false）
+func (s *Server) wait(ctx context.Context) bool {
+	timer := time.NewTimer(s.cfg.RetryInterval)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// removeStaleSocket は前回の実行で残った Unix ドメインソケットのファイルを削除する（ソケット以外のファイルは削除しない）
+func removeStaleSocket(path string) {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		_ = os.Remove(path) // 削除できない場合は受信の開始に失敗する
+	}
+}
@@ -0,0 +1,268 @@
+package syslog_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/logger"
+	"github.com/KeitaShimura/logs-collector-client/internal/syslog"
+)
+
+// 共通エラー定義
+var errHandle = errors.New("handle failed")
+
+// inbox は Handler に渡されたメッセージを記録する
+type inbox struct {
+	mutex    sync.Mutex
+	messages []syslog.Message
+	failures []error // 先頭から順に Handler が返すエラー（使い切った後は記録して nil を返す）
+	calls    int     // Handler が呼ばれた回数
+}
+
+// handle は failures を順に返し、使い切った後は受け取ったメッセージを記録する Handler
+func (i *inbox) handle(_ context.Context, msg syslog.Message) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.calls++
+
+	if len(i.failures) > 0 {
+		err := i.failures[0]
+		i.failures = i.failures[1:]
+
+		return err
+	}
+
+	i.messages = append(i.messages, msg)
+
+	return nil
+}
+
+// count は Handler が呼ばれた回数を返す
+func (i *inbox) count() int {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	return i.calls
+}
+
+// data は記録したメッセージの本文を返す
+func (i *inbox) data() []string {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	data := make([]string, 0, len(i.messages))
+	for _, msg := range i.messages {
+		data = append(data, msg.Data)
+	}
+
+	return data
+}
+
+// start は cfg で受信を開始し、テストの終了時に停止する Server を返す
+func start(t *testing.T, cfg syslog.Config, box *inbox) *syslog.Server {
+	t.Helper()
+
+	server, err := syslog.Listen(cfg, box.handle, logger.NewLogger(logger.WithWriter(io.Discard)))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		server.Run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return server
+}
+
+// dial は Server の受信アドレスに接続する
+func dial(t *testing.T, network string, addr net.Addr) net.Conn {
+	t.Helper()
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(context.Background(), network, addr.String())
+	require.NoError(t, err)
+
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+// TestServer_UDP は 1 データグラムが 1 件のメッセージとして末尾の改行を除いて渡されることを検証する
+func TestServer_UDP(t *testing.T) {
+	t.Parallel()
+
+	box := &inbox{mutex: sync.Mutex{}, messages: nil, failures: nil, calls: 0}
+	server := start(t, syslog.Config{UDPAddr: "127.0.0.1:0", TCPAddr: "", UnixPath: "", MaxMessageSize: 0, RetryInterval: 0}, box)
+	require.Nil(t, server.TCPAddr())
+
+	conn := dial(t, "udp", server.UDPAddr())
+
+	for _, message := range []string{"<13>first\n", "\n", "<13>second"} {
+		_, err := conn.Write([]byte(message))
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool { return len(box.data()) == 2 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, []string{"<13>first", "<13>second"}, box.data())
+	require.Equal(t, syslog.NetworkUDP, box.messages[0].Network)
+	require.Equal(t, conn.LocalAddr().String(), box.messages[0].Remote)
+}
+
+// TestServer_TCPFraming は octet counting と改行区切りのフレームが同じ接続で混在しても分割されることを検証する
+func TestServer_TCPFraming(t *testing.T) {
+	t.Parallel()
+
+	box := &inbox{mutex: sync.Mutex{}, messages: nil, failures: nil, calls: 0}
+	server := start(t, syslog.Config{UDPAddr: "", TCPAddr: "127.0.0.1:0", UnixPath: "", MaxMessageSize: 0, RetryInterval: 0}, box)
+
+	conn := dial(t, "tcp", server.TCPAddr())
+
+	// octet counting のメッセージは改行を含められる
+	_, err := conn.Write([]byte("15 <13>multi\nline\r\n<13>newline framed\r\n11 <13>counted<13>last without newline"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	want := []string{"<13>multi\nline", "<13>newline framed", "<13>counted", "<13>last without newline"}
+	require.Eventually(t, func() bool { return len(box.data()) == len(want) }, time.Second, 5*time.Millisecond)
+	require.Equal(t, want, box.data())
+}
+
+// TestServer_TCPRetry は TCP のメッセージの処理に失敗した場合に、次のメッセージを読み取らずに同じメッセージを再度渡すことを検証する
+func TestServer_TCPRetry(t *testing.T) {
+	t.Parallel()
+
+	box := &inbox{mutex: sync.Mutex{}, messages: nil, failures: []error{errHandle, errHandle}, calls: 0}
+	server := start(t, syslog.Config{
+		UDPAddr: "", TCPAddr: "127.0.0.1:0", UnixPath: "", MaxMessageSize: 0, RetryInterval: 10 * time.Millisecond,
+	}, box)
+
+	conn := dial(t, "tcp", server.TCPAddr())
+
+	_, err := conn.Write([]byte("<13>first\n<13>second\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(box.data()) == 2 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, []string{"<13>first", "<13>second"}, box.data())
+	require.Equal(t, 4, box.count())
+	require.Zero(t, server.Dropped())
+}
+
+// TestServer_Dropped はデータグラムのメッセージと ErrRejected を返した TCP のメッセージを再試行せずに破棄し、件数を数えることを検証する
+func TestServer_Dropped(t *testing.T) {
+	t.Parallel()
+
+	box := &inbox{mutex: sync.Mutex{}, messages: nil, failures: []error{errHandle, syslog.ErrRejected}, calls: 0}
+	server := start(t, syslog.Config{
+		UDPAddr: "127.0.0.1:0", TCPAddr: "127.0.0.1:0", UnixPath: "", MaxMessageSize: 0, RetryInterval: time.Hour,
+	}, box)
+
+	_, err := dial(t, "udp", server.UDPAddr()).Write([]byte("<13>udp"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return server.Dropped() == 1 }, time.Second, 5*time.Millisecond)
+
+	_, err = dial(t, "tcp", server.TCPAddr()).Write([]byte("<13>rejected\n<13>accepted\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(box.data()) == 1 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, []string{"<13>accepted"}, box.data())
+	require.Equal(t, uint64(2), server.Dropped())
+	require.Equal(t, 3, box.count())
+}
+
+// TestServer_TCPTooLarge は MaxMessageSize を超えるフレームで接続を閉じることを検証する
+func TestServer_TCPTooLarge(t *testing.T) {
+	t.Parallel()
+
+	box := &inbox{mutex: sync.Mutex{}, messages: nil, failures: nil, calls: 0}
+	server := start(t, syslog.Config{UDPAddr: "", TCPAddr: "127.0.0.1:0", UnixPath: "", MaxMessageSize: 16, RetryInterval: 0}, box)
+
+	for _, frame := range []string{"<13>ok\n99 <13>too large", "<13>ok\n<13>this line is longer than the limit\n<13>never"} {
+		conn := dial(t, "tcp", server.TCPAddr())
+
+		_, err := conn.Write([]byte(frame))
+		require.NoError(t, err)
+
+		// サーバーが接続を閉じると EOF を受け取る
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+
+		_, err = conn.Read(make([]byte, 1))
+		require.ErrorIs(t, err, io.EOF)
+	}
+
+	require.Equal(t, []string{"<13>ok", "<13>ok"}, box.data())
+}
+
+// TestServer_Unix は Unix ドメインソケットで受信し、停止時にソケットのファイルを削除することを検証する
+func TestServer_Unix(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "syslog.sock")
+
+	// 前回の実行で残ったソケットのファイル（データグラムのソケットは閉じても削除されない）は削除して受信を開始する
+	stale, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	require.NoError(t, err)
+	require.NoError(t, stale.Close())
+
+	box := &inbox{mutex: sync.Mutex{}, messages: nil, failures: nil, calls: 0}
+
+	server, err := syslog.Listen(syslog.Config{UDPAddr: "", TCPAddr: "", UnixPath: path, MaxMessageSize: 0, RetryInterval: 0}, box.handle,
+		logger.NewLogger(logger.WithWriter(io.Discard)))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		server.Run(ctx)
+	}()
+
+	conn := dial(t, "unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+
+	_, err = conn.Write([]byte("<13>app: via unix socket\x00"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(box.data()) == 1 }, time.Second, 5*time.Millisecond)
+	require.Equal(t, []string{"<13>app: via unix socket"}, box.data())
+	require.Equal(t, syslog.NetworkUnix, box.messages[0].Network)
+
+	cancel()
+	<-done
+
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// TestListen は受信先の指定がない場合や受信を開始できない場合にエラーを返すことを検証する
+func TestListen(t *testing.T) {
+	t.Parallel()
+
+	discard := logger.NewLogger(logger.WithWriter(io.Discard))
+	handler := func(context.Context, syslog.Message) error { return nil }
+
+	_, err := syslog.Listen(syslog.Config{UDPAddr: "", TCPAddr: "", UnixPath: "", MaxMessageSize: 0, RetryInterval: 0}, handler, discard)
+	require.ErrorIs(t, err, syslog.ErrNoListeners)
+
+	_, err = syslog.Listen(syslog.Config{UDPAddr: "127.0.0.1:0", TCPAddr: "256.0.0.1:0", UnixPath: "", MaxMessageSize: 0, RetryInterval: 0}, handler, discard)
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), "failed to listen on tcp"), err.Error())
+}
@@ -0,0 +1,90 @@
+package tail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// stateFilePerm は状態ファイルのパーミッション
+const stateFilePerm = 0o600
+
+// Position はファイルごとの読み取り位置（チェックポイント）を表す構造体
+type Position struct {
+	Inode  uint64 `json:"inode"`
+	Offset int64  `json:"offset"`
+}
+
+// state は状態ファイルに保存する内容
+type state struct {
+	Files map[string]Position `json:"files"`
+}
+
+// LoadPositions は状態ファイルからファイルパスごとのチェックポイントを読み込む
+// 状態ファイルが存在しない場合は空のマップを返す
+func LoadPositions(path string) (map[string]Position, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Position{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var loaded state
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return nil, fmt.Errorf("failed to decode state file: %w", err)
+	}
+
+	if loaded.Files == nil {
+		loaded.Files = map[string]Position{}
+	}
+
+	return loaded.Files, nil
+}
+
+// SavePositions はチェックポイントを状態ファイルに保存する
+// 書き込み途中のクラッシュで状態ファイルが壊れないよう、一時ファイルを経由して置き換える
+func SavePositions(path string, positions map[string]Position) error {
+	data, err := json.Marshal(state{Files: positions})
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp state file: %w", err)
+	}
+
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("failed to write temp state file: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("failed to sync temp state file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp state file: %w", err)
+	}
+
+	if err := os.Chmod(tmpName, stateFilePerm); err != nil {
+		return fmt.Errorf("failed to chmod temp state file: %w", err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+
+	return nil
+}
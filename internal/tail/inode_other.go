@@ -0,0 +1,11 @@
+//go:build !unix
+
+package tail
+
+import "os"
+
+// fileID は inode を持たないプラットフォームでは常に 0 を返す
+// この場合ローテーションはファイルサイズの縮小（copytruncate）のみ検出される
+func fileID(_ os.FileInfo) uint64 {
+	return 0
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package tail
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID はファイルを識別する inode 番号を返す
+func fileID(info os.FileInfo) uint64 {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0
+	}
+
+	return stat.Ino
+}
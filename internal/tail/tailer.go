@@ -0,0 +1,390 @@
+package tail
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/logger"
+)
+
+// ErrNoPatterns は、監視対象のパターンが指定されていない場合のエラー
+var ErrNoPatterns = errors.New("no file patterns specified")
+
+// readBufferSize はファイル読み取り時のバッファサイズ
+const readBufferSize = 64 * 1024
+
+// DefaultDrainPolls は、置き換え・削除されたファイルを閉じるまでに待つ、追記のないポーリングの既定の回数
+const DefaultDrainPolls = 3
+
+// LineHandler は読み取った 1 行を処理するコールバック（offset はファイル内の行の先頭の位置）
+// エラーを返した場合、その行以降は次回のポーリングで再度読み取られる
+type LineHandler func(ctx context.Context, path, line string, offset int64) error
+
+// HoldFunc は LineHandler が受け取ったものの送信を終えていない行（複数行のまとめの途中など）のうち、
+// path で最も前にある行の位置を返す（該当する行がない場合は false）
+type HoldFunc func(path string) (int64, bool)
+
+// Config は Tailer の設定を保持する構造体
+type Config struct {
+	Patterns     []string      // 監視対象ファイルのグロブパターン
+	StateFile    string        // チェックポイントを保存する状態ファイルのパス
+	PollInterval time.Duration // ファイルを確認する間隔
+	Hold         HoldFunc      // チェックポイントをその位置より先に進めない行を返す（nil の場合はすべて処理済みとみなす）
+	DrainPolls   int           // 置き換え・削除されたファイルを、追記のないポーリングがこの回数続いてから閉じる（0 以下は DefaultDrainPolls）
+}
+
+// trackedFile は読み取り中のファイルの状態を保持する構造体
+type trackedFile struct {
+	path   string
+	file   *os.File
+	inode  uint64
+	offset int64
+	size   int64 // 置き換えられた後、前回のポーリングで確認したファイルサイズ
+	idle   int   // 置き換えられた後、ファイルサイズが変わらなかったポーリングの回数
+}
+
+// Tailer は複数ファイルを追跡し、追記された行を LineHandler に渡す
+// rename + create と copytruncate の両方のローテーションに追従する
+type Tailer struct {
+	cfg      Config
+	handler  LineHandler
+	logger   logger.Logger
+	files    map[string]*trackedFile // 現在のパスで追跡中のファイル
+	detached []*trackedFile          // 置き換えられた後、読み切っていないファイル
+	known    map[uint64]int64        // inode ごとの既知の読み取り位置
+	restored map[string]Position     // 起動時に状態ファイルから復元したチェックポイント
+}
+
+// New は状態ファイルからチェックポイントを復元して Tailer を生成する
+func New(cfg Config, handler LineHandler, logger logger.Logger) (*Tailer, error) {
+	if len(cfg.Patterns) == 0 {
+		return nil, ErrNoPatterns
+	}
+
+	positions, err := LoadPositions(cfg.StateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[uint64]int64, len(positions))
+	for _, pos := range positions {
+		known[pos.Inode] = pos.Offset
+	}
+
+	if cfg.DrainPolls <= 0 {
+		cfg.DrainPolls = DefaultDrainPolls
+	}
+
+	return &Tailer{
+		cfg:      cfg,
+		handler:  handler,
+		logger:   logger,
+		files:    map[string]*trackedFile{},
+		detached: nil,
+		known:    known,
+		restored: positions,
+	}, nil
+}
+
+// Run はコンテキストがキャンセルされるまで PollInterval ごとに Poll を繰り返す
+// 終了後は、保留中の行を処理してから Close でチェックポイントを保存する
+func (t *Tailer) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.Poll(ctx); err != nil {
+			t.logger.Error("failed to poll files", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll は監視対象ファイルを 1 回走査し、追記された行を処理してチェックポイントを保存する
+func (t *Tailer) Poll(ctx context.Context) error {
+	matched := t.expand()
+	seen := make(map[string]bool, len(matched))
+
+	for _, path := range matched {
+		seen[path] = true
+
+		if err := t.sync(ctx, path); err != nil {
+			t.logger.Warn("failed to read file", "path", path, "error", err.Error())
+		}
+	}
+
+	// パターンに一致しなくなったファイル（削除・リネーム）は読み切ってから閉じる
+	for path, tracked := range t.files {
+		if !seen[path] {
+			delete(t.files, path)
+			t.detach(tracked)
+		}
+	}
+
+	t.drainDetached(ctx)
+
+	return t.save()
+}
+
+// Close は追跡中のファイルを閉じ、チェックポイントを保存する
+func (t *Tailer) Close() error {
+	for _, tracked := range t.files {
+		tracked.file.Close()
+	}
+
+	for _, tracked := range t.detached {
+		tracked.file.Close()
+	}
+
+	t.detached = nil
+
+	return t.save()
+}
+
+// expand はグロブパターンを展開し、重複を除いたファイルパスを昇順で返す
+func (t *Tailer) expand() []string {
+	unique := map[string]bool{}
+
+	for _, pattern := range t.cfg.Patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			t.logger.Warn("invalid file pattern", "pattern", pattern, "error", err.Error())
+
+			continue
+		}
+
+		for _, match := range matches {
+			unique[match] = true
+		}
+	}
+
+	paths := make([]string, 0, len(unique))
+	for path := range unique {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	return paths
+}
+
+// sync は 1 ファイルについてローテーションを検出し、追記された行を読み取る
+func (t *Tailer) sync(ctx context.Context, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	if info.IsDir() {
+		return nil
+	}
+
+	inode := fileID(info)
+
+	// 同じパスが別ファイルに置き換えられた場合（rename + create）
+	// 行の順序を保つため旧ファイルの追記分を先に読み、別パスで見つかるか読み切られるまで保持する
+	tracked := t.files[path]
+	if tracked != nil && tracked.inode != inode {
+		delete(t.files, path)
+		t.detach(tracked)
+
+		if err := t.read(ctx, tracked, false); err != nil {
+			return err
+		}
+
+		tracked = nil
+	}
+
+	if tracked == nil {
+		tracked, err = t.attach(path, inode, info.Size())
+		if err != nil {
+			return err
+		}
+	}
+
+	// ファイルサイズが読み取り位置より小さい場合は切り詰められた（copytruncate）とみなす
+	if info.Size() < tracked.offset {
+		t.logger.Info("file truncated, reading from start", "path", path)
+
+		tracked.offset = 0
+	}
+
+	return t.read(ctx, tracked, false)
+}
+
+// attach はパスに対応する trackedFile を用意する
+// 同じ inode を追跡中であればそれを引き継ぎ、なければ既知の位置から開き直す
+func (t *Tailer) attach(path string, inode uint64, size int64) (*trackedFile, error) {
+	if tracked := t.takeByInode(inode); tracked != nil {
+		tracked.path = path
+		t.files[path] = tracked
+
+		return tracked, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	offset, ok := t.known[inode]
+
+	// inode を取得できない環境ではパス単位のチェックポイントを用いる
+	if inode == 0 {
+		pos, found := t.restored[path]
+		offset, ok = pos.Offset, found
+	}
+
+	// inode が再利用された場合に備え、ファイルサイズを超える位置は無効とする
+	if !ok || offset > size {
+		offset = 0
+	}
+
+	tracked := &trackedFile{path: path, file: file, inode: inode, offset: offset, size: 0, idle: 0}
+	t.files[path] = tracked
+
+	return tracked, nil
+}
+
+// takeByInode は指定 inode を追跡中の trackedFile を取り出す
+func (t *Tailer) takeByInode(inode uint64) *trackedFile {
+	if inode == 0 {
+		return nil
+	}
+
+	for path, tracked := range t.files {
+		if tracked.inode == inode {
+			delete(t.files, path)
+
+			return tracked
+		}
+	}
+
+	for i, tracked := range t.detached {
+		if tracked.inode == inode {
+			t.detached = append(t.detached[:i], t.detached[i+1:]...)
+
+			return tracked
+		}
+	}
+
+	return nil
+}
+
+// detach は置き換え・削除されたファイルを、読み切るまで保持する
+func (t *Tailer) detach(tracked *trackedFile) {
+	tracked.size = -1
+	tracked.idle = 0
+	t.detached = append(t.detached, tracked)
+}
+
+// drainDetached は置き換え・削除されたファイルの追記分を読み取る
+// 書き込み側が新しいファイルを開き直すまでの追記を取りこぼさないよう、ファイルサイズが変わらない
+// ポーリングが DrainPolls 回続いてから、改行で終わっていない末尾を含めて読み切って閉じる
+// 処理に失敗したファイルは次回のポーリングで再試行する
+func (t *Tailer) drainDetached(ctx context.Context) {
+	remaining := t.detached[:0]
+
+	for _, tracked := range t.detached {
+		if info, err := tracked.file.Stat(); err == nil && info.Size() != tracked.size {
+			tracked.size = info.Size()
+			tracked.idle = 0
+		} else {
+			tracked.idle++
+		}
+
+		final := tracked.idle >= t.cfg.DrainPolls
+
+		if err := t.read(ctx, tracked, final); err != nil {
+			t.logger.Warn("failed to drain rotated file", "path", tracked.path, "error", err.Error())
+
+			remaining = append(remaining, tracked)
+
+			continue
+		}
+
+		if !final {
+			remaining = append(remaining, tracked)
+
+			continue
+		}
+
+		tracked.file.Close()
+	}
+
+	t.detached = remaining
+}
+
+// read は読み取り位置から改行までを 1 行として LineHandler に渡す
+// final が false の場合、改行で終わっていない末尾は書き込み途中とみなして読み取らない
+func (t *Tailer) read(ctx context.Context, tracked *trackedFile, final bool) error {
+	if _, err := tracked.file.Seek(tracked.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek file: %w", err)
+	}
+
+	reader := bufio.NewReaderSize(tracked.file, readBufferSize)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("read interrupted: %w", err)
+		}
+
+		line, err := reader.ReadString('\n')
+
+		if errors.Is(err, io.EOF) && (!final || line == "") {
+			return nil
+		}
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+
+		if handleErr := t.handler(ctx, tracked.path, strings.TrimRight(line, "\r\n"), tracked.offset); handleErr != nil {
+			return handleErr
+		}
+
+		tracked.offset += int64(len(line))
+		t.known[tracked.inode] = tracked.offset
+	}
+}
+
+// save は追跡中ファイルのチェックポイントを状態ファイルに保存する
+// 送信を保留している行（Config.Hold）があるファイルは、再起動後にその行から読み直すよう保留中の位置を保存する
+func (t *Tailer) save() error {
+	positions := make(map[string]Position, len(t.files))
+	known := make(map[uint64]int64, len(t.files)+len(t.detached))
+
+	for path, tracked := range t.files {
+		offset := tracked.offset
+		if t.cfg.Hold != nil {
+			if held, ok := t.cfg.Hold(path); ok && held < offset {
+				offset = held
+			}
+		}
+
+		positions[path] = Position{Inode: tracked.inode, Offset: offset}
+		known[tracked.inode] = tracked.offset
+	}
+
+	// 読み切れていない旧ファイルの位置は、別パスで再検出された際に引き継ぐため保持する
+	for _, tracked := range t.detached {
+		known[tracked.inode] = tracked.offset
+	}
+
+	t.known = known
+
+	return SavePositions(t.cfg.StateFile, positions)
+}
@@ -0,0 +1,275 @@
+package tail_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/container"
+	"github.com/KeitaShimura/logs-collector-client/internal/logger"
+	"github.com/KeitaShimura/logs-collector-client/internal/multiline"
+	"github.com/KeitaShimura/logs-collector-client/internal/tail"
+)
+
+// collector は LineHandler に渡された行を記録する
+type collector struct {
+	lines []string
+}
+
+// handle は受け取った行を記録する LineHandler
+func (c *collector) handle(_ context.Context, _, line string, _ int64) error {
+	c.lines = append(c.lines, line)
+
+	return nil
+}
+
+// newTailer はテスト用ディレクトリ配下の *.log を監視する Tailer を生成する
+func newTailer(t *testing.T, dir string, handler tail.LineHandler, hold tail.HoldFunc) *tail.Tailer {
+	t.Helper()
+
+	tailer, err := tail.New(tail.Config{
+		Patterns:     []string{filepath.Join(dir, "*.log")},
+		StateFile:    filepath.Join(dir, "state.json"),
+		PollInterval: time.Second,
+		Hold:         hold,
+		DrainPolls:   0,
+	}, handler, logger.NewLogger(logger.WithWriter(io.Discard)))
+	require.NoError(t, err)
+
+	return tailer
+}
+
+// appendFile はファイルに文字列を追記する
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+}
+
+// TestTailer_PartialLine は改行で終わっていない行が、改行が書き込まれるまで保留されることを検証する
+func TestTailer_PartialLine(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	lines := &collector{lines: nil}
+	tailer := newTailer(t, dir, lines.handle, nil)
+
+	appendFile(t, path, "first\nsec")
+	require.NoError(t, tailer.Poll(context.Background()))
+	require.Equal(t, []string{"first"}, lines.lines)
+
+	appendFile(t, path, "ond\n")
+	require.NoError(t, tailer.Poll(context.Background()))
+	require.Equal(t, []string{"first", "second"}, lines.lines)
+}
+
+// TestTailer_RenameRotation は rename + create のローテーションで旧ファイルを読み切ってから新ファイルに追従することを検証する
+func TestTailer_RenameRotation(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	lines := &collector{lines: nil}
+	tailer := newTailer(t, dir, lines.handle, nil)
+
+	appendFile(t, path, "one\n")
+	require.NoError(t, tailer.Poll(context.Background()))
+
+	appendFile(t, path, "two\n")
+	require.NoError(t, os.Rename(path, path+".1"))
+	appendFile(t, path, "three\n")
+	require.NoError(t, tailer.Poll(context.Background()))
+
+	require.Equal(t, []string{"one", "two", "three"}, lines.lines)
+}
+
+// TestTailer_RenameRotationLateWrite はパターンに一致しない名前にリネームされたファイルに、
+// 書き込み側が新しいファイルを開き直すまでに追記した行を取りこぼさないことを検証する
+func TestTailer_RenameRotationLateWrite(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	lines := &collector{lines: nil}
+	tailer := newTailer(t, dir, lines.handle, nil)
+
+	// 書き込み側はリネーム後も開いたままのファイルに追記する
+	writer, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { writer.Close() })
+
+	_, err = writer.WriteString("one\n")
+	require.NoError(t, err)
+	require.NoError(t, tailer.Poll(context.Background()))
+
+	require.NoError(t, os.Rename(path, filepath.Join(dir, "app.log.1")))
+	require.NoError(t, tailer.Poll(context.Background()))
+
+	_, err = writer.WriteString("two\nunterminated")
+	require.NoError(t, err)
+	require.NoError(t, tailer.Poll(context.Background()))
+
+	appendFile(t, path, "three\n")
+	require.NoError(t, tailer.Poll(context.Background()))
+	require.Equal(t, []string{"one", "two", "three"}, lines.lines)
+
+	// 改行で終わっていない末尾は、追記のないポーリングが（three を読み取った回を含めて）DefaultDrainPolls 回続いてから 1 行として読み取る
+	for range tail.DefaultDrainPolls - 2 {
+		require.NoError(t, tailer.Poll(context.Background()))
+	}
+
+	require.Equal(t, []string{"one", "two", "three"}, lines.lines)
+
+	require.NoError(t, tailer.Poll(context.Background()))
+	require.Equal(t, []string{"one", "two", "three", "unterminated"}, lines.lines)
+}
+
+// TestTailer_CopyTruncate は copytruncate で切り詰められたファイルを先頭から読み直すことを検証する
+func TestTailer_CopyTruncate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	lines := &collector{lines: nil}
+	tailer := newTailer(t, dir, lines.handle, nil)
+
+	appendFile(t, path, "before truncate\n")
+	require.NoError(t, tailer.Poll(context.Background()))
+
+	require.NoError(t, os.Truncate(path, 0))
+	appendFile(t, path, "after\n")
+	require.NoError(t, tailer.Poll(context.Background()))
+
+	require.Equal(t, []string{"before truncate", "after"}, lines.lines)
+}
+
+// TestTailer_ResumeFromCheckpoint は再起動後にチェックポイントから重複なく再開することを検証する
+func TestTailer_ResumeFromCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	first := &collector{lines: nil}
+	tailer := newTailer(t, dir, first.handle, nil)
+
+	appendFile(t, path, "one\ntwo\n")
+	require.NoError(t, tailer.Poll(context.Background()))
+	require.NoError(t, tailer.Close())
+
+	appendFile(t, path, "three\n")
+
+	second := &collector{lines: nil}
+	restarted := newTailer(t, dir, second.handle, nil)
+	require.NoError(t, restarted.Poll(context.Background()))
+	require.NoError(t, restarted.Close())
+
+	require.Equal(t, []string{"one", "two"}, first.lines)
+	require.Equal(t, []string{"three"}, second.lines)
+}
+
+// TestTailer_HoldMultiline は複数行をまとめている途中で再起動した場合に、まとめていた行から読み直すことを検証する
+func TestTailer_HoldMultiline(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	rule, err := multiline.NewRule("", `^\s`)
+	require.NoError(t, err)
+
+	rule.FlushTimeout = 0
+
+	var events []string
+
+	// newGroup は確定したイベントを events に記録する Group と、それを使用する Tailer を生成する
+	newGroup := func() (*multiline.Group, *tail.Tailer) {
+		group := multiline.NewGroup(*rule, func(_ context.Context, _, event string) error {
+			events = append(events, event)
+
+			return nil
+		})
+		handler := func(ctx context.Context, path, line string, offset int64) error {
+			return group.Add(ctx, path, line, offset)
+		}
+
+		return group, newTailer(t, dir, handler, group.Pending)
+	}
+
+	appendFile(t, path, "done\npanic: boom\n  at main\n")
+
+	// "panic: boom" 以降をまとめている途中で停止する（イベントを送信できずに終了した場合と同じ）
+	_, tailer := newGroup()
+	require.NoError(t, tailer.Poll(context.Background()))
+	require.NoError(t, tailer.Close())
+	require.Equal(t, []string{"done"}, events)
+
+	appendFile(t, path, "  at runtime\nnext\n")
+
+	group, restarted := newGroup()
+	require.NoError(t, restarted.Poll(context.Background()))
+	require.NoError(t, group.Close(context.Background()))
+	require.NoError(t, restarted.Close())
+	require.Equal(t, []string{"done", "panic: boom\n  at main\n  at runtime", "next"}, events)
+
+	// すべて送信した後はチェックポイントが末尾まで進む
+	_, again := newGroup()
+	require.NoError(t, again.Poll(context.Background()))
+	require.NoError(t, again.Close())
+	require.Len(t, events, 3)
+}
+
+// TestTailer_HoldPartialRecord はコンテナログの分割された記録の途中で再起動した場合に、先頭の記録から読み直すことを検証する
+func TestTailer_HoldPartialRecord(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	var lines []string
+
+	// newReassembler は連結した行を lines に記録する Reassembler と、それを使用する Tailer を生成する
+	newReassembler := func() *tail.Tailer {
+		reassembler := container.NewReassembler(0)
+		handler := func(_ context.Context, path, line string, offset int64) error {
+			record, err := container.Parse(container.FormatCRI, line)
+			require.NoError(t, err)
+
+			joined, _, complete := reassembler.Join(path, record, offset)
+			if complete {
+				lines = append(lines, joined.Message)
+				reassembler.Release(path, joined.Stream)
+			}
+
+			return nil
+		}
+
+		return newTailer(t, dir, handler, reassembler.Pending)
+	}
+
+	appendFile(t, path, "2025-01-02T03:04:05Z stdout P first half, \n2025-01-02T03:04:05Z stderr F error\n")
+
+	tailer := newReassembler()
+	require.NoError(t, tailer.Poll(context.Background()))
+	require.NoError(t, tailer.Close())
+	require.Equal(t, []string{"error"}, lines)
+
+	appendFile(t, path, "2025-01-02T03:04:06Z stdout F second half\n")
+
+	// 分割された先頭の記録から読み直すため、その後に送信済みの行は再送される
+	restarted := newReassembler()
+	require.NoError(t, restarted.Poll(context.Background()))
+	require.NoError(t, restarted.Close())
+	require.Equal(t, []string{"error", "error", "first half, second half"}, lines)
+}
@@ -0,0 +1,233 @@
+package wal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/logger"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// ShipOptions は WAL から送信先へ転送する際の設定を保持する構造体
+type ShipOptions struct {
+	BatchSize     int           // 1 回に読み出して送信する最大件数
+	RetryInterval time.Duration // 送信に失敗した場合に再送するまでの待機時間
+	DrainTimeout  time.Duration // Close 時に未送信のログを送信し切るまで待つ最大時間
+}
+
+// インターフェースを満たしていることをコンパイル時に検証する
+var _ client.Client = (*BufferedClient)(nil)
+
+// BufferedClient は SendLog を WAL への追記として受け付け、バックグラウンドで送信先へ転送するクライアント
+// 送信先が停止している間はディスク上に保持し、復旧後に追記順で再送する
+// 送信先が再試行しても回復しないエラーで拒否したログは、後続のログを止めないよう破棄する
+type BufferedClient struct {
+	inner    client.Client
+	queue    *Queue
+	opts     ShipOptions
+	logger   logger.Logger
+	rejected atomic.Uint64 // 送信先に拒否されて破棄したログの累計件数
+
+	wake      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+}
+
+// NewBufferedClient は inner への転送を開始した BufferedClient を生成する
+func NewBufferedClient(inner client.Client, queue *Queue, opts ShipOptions, logger logger.Logger) *BufferedClient {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	buffered := &BufferedClient{
+		inner:     inner,
+		queue:     queue,
+		opts:      opts,
+		logger:    logger,
+		rejected:  atomic.Uint64{},
+		wake:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		cancel:    cancel,
+		closeOnce: sync.Once{},
+	}
+
+	go buffered.ship(ctx)
+
+	return buffered
+}
+
+// SendLog はログを WAL に追記し、転送処理に通知する
+// 不正なログは再送しても成功しないため、WAL に追記せずエラーを返す
+func (c *BufferedClient) SendLog(_ context.Context, log *model.Log) error {
+	log.Normalize(time.Now())
+
+	if err := log.Validate(); err != nil {
+		return fmt.Errorf("failed to buffer log: %w", err)
+	}
+
+	if err := c.queue.Append(log); err != nil {
+		return fmt.Errorf("failed to buffer log: %w", err)
+	}
+
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// GetLogs はラップしているクライアントでログを取得する
+func (c *BufferedClient) GetLogs(ctx context.Context, query *model.LogQuery) ([]*model.Log, error) {
+	return c.inner.GetLogs(ctx, query) //nolint:wrapcheck // ラップ元のエラーをそのまま返す
+}
+
+// Dropped は WAL の上限超過や破損、送信先による拒否により破棄されたログの累計件数を返す
+func (c *BufferedClient) Dropped() uint64 {
+	return c.queue.Dropped() + c.rejected.Load()
+}
+
+// Close は DrainTimeout まで未送信のログの転送を試みてから停止する
+// 送信し切れなかったログは WAL に残り、次回起動時に再送される
+func (c *BufferedClient) Close() error {
+	var err error
+
+	c.closeOnce.Do(func() {
+		close(c.stop)
+
+		select {
+		case <-c.done:
+		case <-time.After(c.opts.DrainTimeout):
+			c.cancel()
+			<-c.done
+		}
+
+		c.cancel()
+
+		if dropped := c.Dropped(); dropped > 0 {
+			c.logger.Warn("WAL dropped logs", "dropped", dropped)
+		}
+
+		err = c.queue.Close()
+		if closeErr := c.inner.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("failed to close client: %w", closeErr)
+		}
+	})
+
+	return err
+}
+
+// ship は WAL のログを送信先へ転送し続ける
+// Close が呼ばれると、未送信のログがなくなるか送信に失敗するまで転送してから終了する
+func (c *BufferedClient) ship(ctx context.Context) {
+	defer close(c.done)
+
+	for {
+		shipped, err := c.shipBatch(ctx)
+
+		select {
+		case <-c.stop:
+			if err == nil && shipped {
+				continue
+			}
+
+			return
+		default:
+		}
+
+		switch {
+		case err != nil:
+			c.logger.Warn("failed to ship buffered logs, will retry", "error", err.Error())
+			c.wait(c.opts.RetryInterval)
+		case !shipped:
+			c.wait(0)
+		}
+	}
+}
+
+// wait は新しいログの追記、Close、または timeout の経過まで待機する（timeout が 0 の場合は無期限）
+func (c *BufferedClient) wait(timeout time.Duration) {
+	var timer <-chan time.Time
+	if timeout > 0 {
+		timer = time.After(timeout)
+	}
+
+	select {
+	case <-c.wake:
+	case <-c.stop:
+	case <-timer:
+	}
+}
+
+// shipBatch は WAL から最大 BatchSize 件を読み出して送信し、送信できた位置まで読み取り位置を進める
+// バッチが再試行しても回復しないエラーで拒否された場合は 1 件ずつ送信し直し、拒否されたログのみ破棄する
+// 送信または破棄したログがあれば true を返す
+func (c *BufferedClient) shipBatch(ctx context.Context) (bool, error) {
+	entries, err := c.queue.ReadBatch(c.opts.BatchSize)
+	if err != nil || len(entries) == 0 {
+		return false, err
+	}
+
+	logs := make([]*model.Log, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Log != nil {
+			logs = append(logs, entry.Log)
+		}
+	}
+
+	// まとめて送信できる場合はバッチ単位で送信する
+	if batchSender, ok := c.inner.(client.BatchSender); ok && len(logs) > 0 {
+		err := batchSender.SendLogs(ctx, logs)
+		if err == nil {
+			return true, c.queue.Commit(entries[len(entries)-1].Next)
+		}
+
+		if !rejected(ctx, err) {
+			return false, fmt.Errorf("failed to ship %d logs: %w", len(logs), err)
+		}
+
+		c.logger.Warn("buffered logs rejected, shipping one at a time", "logs", len(logs), "error", err.Error())
+	}
+
+	// 1 件ずつ送信し、失敗した場合は送信または破棄したログの直後まで読み取り位置を進める
+	for i, entry := range entries {
+		if entry.Log == nil {
+			continue
+		}
+
+		if err := c.inner.SendLog(ctx, entry.Log); err != nil {
+			if rejected(ctx, err) {
+				dropped := c.rejected.Add(1)
+				c.logger.Warn("dropping buffered log rejected by server", "dropped", dropped, "error", err.Error())
+
+				continue
+			}
+
+			if i > 0 {
+				return true, errors.Join(fmt.Errorf("failed to ship log: %w", err), c.queue.Commit(entries[i-1].Next))
+			}
+
+			return false, fmt.Errorf("failed to ship log: %w", err)
+		}
+	}
+
+	return true, c.queue.Commit(entries[len(entries)-1].Next)
+}
+
+// rejected は err が、送信先がログを拒否した再試行しても回復しないエラーかを返す
+// 停止による失敗、設定の誤り（client.ErrInvalidClientConfig）、送信先が返したものでないエラーは、ログを失わないよう再送する
+func rejected(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, client.ErrInvalidClientConfig) {
+		return false
+	}
+
+	clientErr := (*client.Error)(nil)
+
+	return errors.As(err, &clientErr) && !clientErr.Retryable
+}
@@ -0,0 +1,183 @@
+package wal_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/client"
+	"github.com/KeitaShimura/logs-collector-client/internal/logger"
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	"github.com/KeitaShimura/logs-collector-client/internal/wal"
+)
+
+// 共通エラー定義
+var (
+	errUnavailable = errors.New("collector unavailable")
+	errRejected    = &client.Error{
+		Transport: client.TransportGRPC, Operation: client.OperationSendLog, StatusCode: 0, Code: codes.InvalidArgument,
+		Message: "invalid log", Details: nil, Retryable: false, RetryAfter: 0, Err: errors.New("invalid log"),
+	}
+)
+
+// flakyClient は down の間は送信に失敗し、それ以外は送信されたログを記録するテスト用の Client
+type flakyClient struct {
+	mutex sync.Mutex
+	down  bool
+	sent  []string
+}
+
+// SendLog は down の間は失敗し、それ以外はメッセージを記録する
+func (c *flakyClient) SendLog(_ context.Context, log *model.Log) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.down {
+		return errUnavailable
+	}
+
+	c.sent = append(c.sent, log.Message)
+
+	return nil
+}
+
+// GetLogs は常に空の結果を返す
+func (c *flakyClient) GetLogs(context.Context, *model.LogQuery) ([]*model.Log, error) {
+	return nil, nil
+}
+
+// Close は何もしない
+func (c *flakyClient) Close() error {
+	return nil
+}
+
+// setDown は送信先の停止状態を切り替える
+func (c *flakyClient) setDown(down bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.down = down
+}
+
+// sentMessages は送信されたメッセージの一覧を返す
+func (c *flakyClient) sentMessages() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]string(nil), c.sent...)
+}
+
+// TestBufferedClient_ReplaysAfterRecovery は送信先の停止中に受け付けたログが、復旧後に順序通り送信されることを検証する
+func TestBufferedClient_ReplaysAfterRecovery(t *testing.T) {
+	t.Parallel()
+
+	inner := &flakyClient{mutex: sync.Mutex{}, down: true, sent: nil}
+	queue := openQueue(t, t.TempDir(), 1<<20, 1<<20)
+	buffered := wal.NewBufferedClient(inner, queue, wal.ShipOptions{
+		BatchSize:     10,
+		RetryInterval: 5 * time.Millisecond,
+		DrainTimeout:  time.Second,
+	}, logger.NewLogger(logger.WithWriter(io.Discard)))
+
+	for _, message := range []string{"a", "b", "c"} {
+		require.NoError(t, buffered.SendLog(context.Background(), newLog(message)))
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	require.Empty(t, inner.sentMessages())
+
+	inner.setDown(false)
+
+	require.Eventually(t, func() bool {
+		return len(inner.sentMessages()) == 3
+	}, time.Second, 5*time.Millisecond)
+	require.Equal(t, []string{"a", "b", "c"}, inner.sentMessages())
+
+	require.NoError(t, buffered.SendLog(context.Background(), newLog("d")))
+	require.NoError(t, buffered.Close())
+	require.Equal(t, []string{"a", "b", "c", "d"}, inner.sentMessages())
+}
+
+// rejectingClient はメッセージが bad のログを再試行しても回復しないエラーで拒否するテスト用の Client
+type rejectingClient struct {
+	flakyClient
+}
+
+// SendLog は bad のログを拒否し、それ以外はメッセージを記録する
+func (c *rejectingClient) SendLog(ctx context.Context, log *model.Log) error {
+	if log.Message == "bad" {
+		return errRejected
+	}
+
+	return c.flakyClient.SendLog(ctx, log)
+}
+
+// batchRejectingClient は bad のログを含むバッチを拒否するテスト用の BatchSender
+type batchRejectingClient struct {
+	rejectingClient
+}
+
+// SendLogs は bad のログを含む場合はバッチ全体を拒否し、それ以外はメッセージを記録する
+func (c *batchRejectingClient) SendLogs(ctx context.Context, logs []*model.Log) error {
+	for _, log := range logs {
+		if log.Message == "bad" {
+			return errRejected
+		}
+	}
+
+	for _, log := range logs {
+		if err := c.flakyClient.SendLog(ctx, log); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TestBufferedClient_Rejected は送信先に拒否されたログのみを破棄し、後続のログの送信を続けることを検証する
+func TestBufferedClient_Rejected(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		inner interface {
+			client.Client
+			sentMessages() []string
+		}
+	}{
+		{name: "1 件ずつ送信", inner: &rejectingClient{flakyClient: flakyClient{mutex: sync.Mutex{}, down: false, sent: nil}}},
+		{name: "バッチで送信", inner: &batchRejectingClient{rejectingClient: rejectingClient{flakyClient: flakyClient{mutex: sync.Mutex{}, down: false, sent: nil}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			queue := openQueue(t, t.TempDir(), 1<<20, 1<<20)
+			buffered := wal.NewBufferedClient(tt.inner, queue, wal.ShipOptions{
+				BatchSize:     10,
+				RetryInterval: time.Hour,
+				DrainTimeout:  time.Second,
+			}, logger.NewLogger(logger.WithWriter(io.Discard)))
+
+			for _, message := range []string{"a", "bad", "c"} {
+				require.NoError(t, buffered.SendLog(context.Background(), newLog(message)))
+			}
+
+			require.Eventually(t, func() bool {
+				return len(tt.inner.sentMessages()) == 2
+			}, time.Second, 5*time.Millisecond)
+
+			require.NoError(t, buffered.SendLog(context.Background(), newLog("d")))
+			require.NoError(t, buffered.Close())
+			require.Equal(t, []string{"a", "c", "d"}, tt.inner.sentMessages())
+			require.Equal(t, uint64(1), buffered.Dropped())
+		})
+	}
+}
@@ -0,0 +1,416 @@
+package wal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+)
+
+// ErrRecordTooLarge は、1 件のログが WAL の上限サイズを超える場合のエラー
+var ErrRecordTooLarge = errors.New("log exceeds WAL size limit")
+
+// ファイル関連の定義
+const (
+	dirPerm        = 0o700
+	filePerm       = 0o600
+	cursorFileName = "cursor.json"
+)
+
+// Options は Queue の設定を保持する構造体
+type Options struct {
+	Dir          string // セグメントと読み取り位置を保存するディレクトリ
+	MaxBytes     int64  // セグメントの合計サイズの上限。超過時は古いセグメントから破棄する
+	SegmentBytes int64  // 1 セグメントあたりのサイズの目安。超過時に新しいセグメントへ切り替える
+}
+
+// Cursor は次に読み取るレコードの位置を表す構造体
+type Cursor struct {
+	Segment uint64 `json:"segment"`
+	Offset  int64  `json:"offset"`
+}
+
+// Entry は読み取ったログと、そのログを処理済みにした場合の読み取り位置を保持する構造体
+// 復元できなかったレコードの場合 Log は nil となる
+type Entry struct {
+	Log  *model.Log
+	Next Cursor
+}
+
+// Queue はログをセグメントファイルに追記し、追記順に読み出すディスク上のキュー
+type Queue struct {
+	mutex    sync.Mutex
+	opts     Options
+	segments []segment // 連番の昇順。末尾が書き込み中のセグメント
+	active   *os.File
+	cursor   Cursor
+	dropped  atomic.Uint64
+}
+
+// Open はディレクトリ内の既存セグメントと読み取り位置を復元して Queue を開く
+// 書き込み途中で途切れた末尾のレコードは切り詰める
+func Open(opts Options) (*Queue, error) {
+	if err := os.MkdirAll(opts.Dir, dirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create WAL directory: %w", err)
+	}
+
+	segments, err := listSegments(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := &Queue{
+		mutex:    sync.Mutex{},
+		opts:     opts,
+		segments: segments,
+		active:   nil,
+		cursor:   Cursor{Segment: 0, Offset: 0},
+		dropped:  atomic.Uint64{},
+	}
+
+	if len(queue.segments) == 0 {
+		queue.segments = []segment{{seq: 1, size: 0}}
+	}
+
+	if err := queue.repairActive(); err != nil {
+		return nil, err
+	}
+
+	if err := queue.loadCursor(); err != nil {
+		return nil, err
+	}
+
+	return queue, nil
+}
+
+// repairActive は書き込み中セグメントの壊れた末尾を切り詰めて追記用に開く
+func (q *Queue) repairActive() error {
+	last := &q.segments[len(q.segments)-1]
+	path := segmentPath(q.opts.Dir, last.seq)
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, filePerm)
+	if err != nil {
+		return fmt.Errorf("failed to open segment: %w", err)
+	}
+
+	valid, _, err := validLength(path, 0)
+	if err != nil {
+		file.Close()
+
+		return err
+	}
+
+	if err := file.Truncate(valid); err != nil {
+		file.Close()
+
+		return fmt.Errorf("failed to truncate segment: %w", err)
+	}
+
+	if _, err := file.Seek(valid, io.SeekStart); err != nil {
+		file.Close()
+
+		return fmt.Errorf("failed to seek segment: %w", err)
+	}
+
+	last.size = valid
+	q.active = file
+
+	return nil
+}
+
+// loadCursor は保存済みの読み取り位置を読み込む。存在しない場合は先頭から読み取る
+func (q *Queue) loadCursor() error {
+	q.cursor = Cursor{Segment: q.segments[0].seq, Offset: 0}
+
+	data, err := os.ReadFile(filepath.Join(q.opts.Dir, cursorFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to read WAL cursor: %w", err)
+	}
+
+	var saved Cursor
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("failed to decode WAL cursor: %w", err)
+	}
+
+	// 読み取り位置のセグメントが既に削除されている場合は残っている先頭から読み取る
+	if saved.Segment >= q.segments[0].seq {
+		q.cursor = saved
+	}
+
+	return nil
+}
+
+// Append はログを書き込み中のセグメントに追記する
+// 合計サイズが MaxBytes を超える場合は、古いセグメントから破棄して領域を確保する
+// 追記ごとに fsync しないため、プロセスの異常終了では失われないが、OS のクラッシュや電源断では直前のログが失われ得る
+func (q *Queue) Append(log *model.Log) error {
+	payload, err := json.Marshal(log)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log: %w", err)
+	}
+
+	record := encodeRecord(payload)
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if int64(len(record)) > q.opts.MaxBytes || len(payload) > maxRecordBytes {
+		q.dropped.Add(1)
+
+		return ErrRecordTooLarge
+	}
+
+	active := &q.segments[len(q.segments)-1]
+	if active.size > 0 && active.size+int64(len(record)) > q.opts.SegmentBytes {
+		if err := q.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if err := q.evict(int64(len(record))); err != nil {
+		return err
+	}
+
+	if _, err := q.active.Write(record); err != nil {
+		return fmt.Errorf("failed to write WAL record: %w", err)
+	}
+
+	q.segments[len(q.segments)-1].size += int64(len(record))
+
+	return nil
+}
+
+// rotate は書き込み中のセグメントを閉じ、新しいセグメントを作成する
+func (q *Queue) rotate() error {
+	if err := q.active.Sync(); err != nil {
+		return fmt.Errorf("failed to sync segment: %w", err)
+	}
+
+	if err := q.active.Close(); err != nil {
+		return fmt.Errorf("failed to close segment: %w", err)
+	}
+
+	next := segment{seq: q.segments[len(q.segments)-1].seq + 1, size: 0}
+
+	file, err := os.OpenFile(segmentPath(q.opts.Dir, next.seq), os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerm)
+	if err != nil {
+		return fmt.Errorf("failed to create segment: %w", err)
+	}
+
+	q.segments = append(q.segments, next)
+	q.active = file
+
+	return nil
+}
+
+// evict は incoming バイトを追記しても MaxBytes を超えないよう、古いセグメントから破棄する
+// 未送信のまま破棄したログの件数は Dropped に加算する
+func (q *Queue) evict(incoming int64) error {
+	for q.totalBytes()+incoming > q.opts.MaxBytes {
+		// 書き込み中のセグメントしか残っていない場合は切り替えてから破棄する
+		if len(q.segments) == 1 {
+			if err := q.rotate(); err != nil {
+				return err
+			}
+		}
+
+		oldest := q.segments[0]
+		path := segmentPath(q.opts.Dir, oldest.seq)
+
+		from := int64(0)
+		if q.cursor.Segment == oldest.seq {
+			from = q.cursor.Offset
+		}
+
+		if q.cursor.Segment <= oldest.seq {
+			_, unread, err := validLength(path, from)
+			if err != nil {
+				return err
+			}
+
+			q.dropped.Add(uint64(unread)) //nolint:gosec // 件数は負にならない
+			q.cursor = Cursor{Segment: q.segments[1].seq, Offset: 0}
+		}
+
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove segment: %w", err)
+		}
+
+		q.segments = q.segments[1:]
+	}
+
+	return nil
+}
+
+// totalBytes はセグメントの合計サイズを返す
+func (q *Queue) totalBytes() int64 {
+	var total int64
+	for _, seg := range q.segments {
+		total += seg.size
+	}
+
+	return total
+}
+
+// ReadBatch は読み取り位置から最大 limit 件のログを読み取る（読み取り位置は進めない）
+// 破損したレコードを含むセグメントは、その位置以降を読み飛ばして次のセグメントへ進む
+func (q *Queue) ReadBatch(limit int) ([]Entry, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	entries := make([]Entry, 0, limit)
+	cursor := q.cursor
+
+	for len(entries) < limit {
+		batch, next, err := q.readSegment(cursor, limit-len(entries))
+		if err != nil {
+			return entries, err
+		}
+
+		entries = append(entries, batch...)
+
+		// 書き込み中のセグメントを読み終えたら終了する
+		if next == cursor || next.Segment > q.segments[len(q.segments)-1].seq {
+			break
+		}
+
+		cursor = next
+	}
+
+	return entries, nil
+}
+
+// readSegment は 1 セグメント内のレコードを読み取り、続きの読み取り位置を返す
+// セグメント末尾まで読み終えた場合、続きの位置は次のセグメントの先頭になる
+func (q *Queue) readSegment(cursor Cursor, limit int) ([]Entry, Cursor, error) {
+	file, err := os.Open(segmentPath(q.opts.Dir, cursor.Segment))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, Cursor{Segment: cursor.Segment + 1, Offset: 0}, nil
+	}
+
+	if err != nil {
+		return nil, cursor, fmt.Errorf("failed to open segment: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(cursor.Offset, io.SeekStart); err != nil {
+		return nil, cursor, fmt.Errorf("failed to seek segment: %w", err)
+	}
+
+	reader := bufio.NewReader(file)
+	entries := make([]Entry, 0, limit)
+	isActive := cursor.Segment == q.segments[len(q.segments)-1].seq
+
+	for len(entries) < limit {
+		payload, size, err := readRecord(reader)
+		if err != nil {
+			// 書き込み中のセグメントは末尾に達したら読み取りを終える
+			if isActive {
+				return entries, cursor, nil
+			}
+
+			if !errors.Is(err, io.EOF) {
+				q.dropped.Add(1)
+			}
+
+			return entries, Cursor{Segment: cursor.Segment + 1, Offset: 0}, nil
+		}
+
+		cursor.Offset += size
+
+		// 復元できないレコードは Log を nil とし、読み取り位置だけを進められるようにする
+		var log *model.Log
+		if err := json.Unmarshal(payload, &log); err != nil {
+			q.dropped.Add(1)
+
+			log = nil
+		}
+
+		entries = append(entries, Entry{Log: log, Next: cursor})
+	}
+
+	return entries, cursor, nil
+}
+
+// Commit は読み取り位置を cursor まで進めて保存し、読み終えたセグメントを削除する
+func (q *Queue) Commit(cursor Cursor) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.cursor = cursor
+
+	for len(q.segments) > 1 && q.segments[0].seq < cursor.Segment {
+		if err := os.Remove(segmentPath(q.opts.Dir, q.segments[0].seq)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove segment: %w", err)
+		}
+
+		q.segments = q.segments[1:]
+	}
+
+	return q.saveCursor()
+}
+
+// saveCursor は読み取り位置を一時ファイル経由で保存する
+func (q *Queue) saveCursor() error {
+	data, err := json.Marshal(q.cursor)
+	if err != nil {
+		return fmt.Errorf("failed to encode WAL cursor: %w", err)
+	}
+
+	path := filepath.Join(q.opts.Dir, cursorFileName)
+	tmpPath := path + ".tmp"
+
+	if err := os.WriteFile(tmpPath, data, filePerm); err != nil {
+		return fmt.Errorf("failed to write WAL cursor: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace WAL cursor: %w", err)
+	}
+
+	return nil
+}
+
+// Pending は未送信のログが残っているかを返す
+func (q *Queue) Pending() bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	last := q.segments[len(q.segments)-1]
+
+	return q.cursor.Segment < last.seq || q.cursor.Offset < last.size
+}
+
+// Dropped は上限超過や破損により送信されずに破棄されたログの累計件数を返す
+func (q *Queue) Dropped() uint64 {
+	return q.dropped.Load()
+}
+
+// Close は書き込み中のセグメントを同期して閉じる
+func (q *Queue) Close() error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if err := q.active.Sync(); err != nil {
+		q.active.Close()
+
+		return fmt.Errorf("failed to sync segment: %w", err)
+	}
+
+	if err := q.active.Close(); err != nil {
+		return fmt.Errorf("failed to close segment: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package wal_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/KeitaShimura/logs-collector-client/internal/model"
+	"github.com/KeitaShimura/logs-collector-client/internal/wal"
+)
+
+// newLog はテスト用のログを生成する
+func newLog(message string) *model.Log {
+	return &model.Log{
+		ID:        message,
+		TraceID:   "",
+		Timestamp: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Level:     "INFO",
+		Service:   "test-service",
+		Message:   message,
+		Metadata:  nil,
+	}
+}
+
+// openQueue はテスト用ディレクトリに Queue を開く
+func openQueue(t *testing.T, dir string, maxBytes, segmentBytes int64) *wal.Queue {
+	t.Helper()
+
+	queue, err := wal.Open(wal.Options{Dir: dir, MaxBytes: maxBytes, SegmentBytes: segmentBytes})
+	require.NoError(t, err)
+
+	return queue
+}
+
+// messages は読み取ったエントリのメッセージ一覧を返す
+func messages(entries []wal.Entry) []string {
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, entry.Log.Message)
+	}
+
+	return result
+}
+
+// TestQueue_ResumeAfterReopen はコミット済みの位置から再開し、未コミットのログが再度読み出されることを検証する
+func TestQueue_ResumeAfterReopen(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	queue := openQueue(t, dir, 1<<20, 128)
+
+	for i := range 5 {
+		require.NoError(t, queue.Append(newLog("log-"+strconv.Itoa(i))))
+	}
+
+	entries, err := queue.ReadBatch(2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"log-0", "log-1"}, messages(entries))
+	require.NoError(t, queue.Commit(entries[1].Next))
+	require.NoError(t, queue.Close())
+
+	reopened := openQueue(t, dir, 1<<20, 128)
+	defer reopened.Close()
+
+	entries, err = reopened.ReadBatch(10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"log-2", "log-3", "log-4"}, messages(entries))
+
+	require.NoError(t, reopened.Commit(entries[2].Next))
+	require.False(t, reopened.Pending())
+}
+
+// TestQueue_TruncatedTail は書き込み途中で途切れたレコードが再オープン時に切り詰められることを検証する
+func TestQueue_TruncatedTail(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	queue := openQueue(t, dir, 1<<20, 1<<20)
+	require.NoError(t, queue.Append(newLog("complete")))
+	require.NoError(t, queue.Close())
+
+	// 不完全なレコードを末尾に書き足す
+	segments, err := filepath.Glob(filepath.Join(dir, "*.seg"))
+	require.NoError(t, err)
+	require.Len(t, segments, 1)
+
+	file, err := os.OpenFile(segments[0], os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.Write([]byte{0, 0, 0, 10, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	reopened := openQueue(t, dir, 1<<20, 1<<20)
+	defer reopened.Close()
+
+	require.NoError(t, reopened.Append(newLog("after")))
+
+	entries, err := reopened.ReadBatch(10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"complete", "after"}, messages(entries))
+}
+
+// TestQueue_EvictsOldestSegment は上限サイズを超えた場合に古いログから破棄し、件数を記録することを検証する
+func TestQueue_EvictsOldestSegment(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	// 1 レコードは約 150 バイトのため、1 セグメント 1 件・合計 2 件程度しか保持できない
+	queue := openQueue(t, dir, 350, 100)
+	defer queue.Close()
+
+	for i := range 4 {
+		require.NoError(t, queue.Append(newLog("log-"+strconv.Itoa(i))))
+	}
+
+	entries, err := queue.ReadBatch(10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"log-2", "log-3"}, messages(entries))
+	require.Equal(t, uint64(2), queue.Dropped())
+}
@@ -0,0 +1,141 @@
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrCorruptRecord は、レコードの CRC が一致しない場合のエラー
+var ErrCorruptRecord = errors.New("corrupt WAL record")
+
+// セグメントファイルのフォーマット定義
+//
+//	レコード = [ペイロード長 (4 byte, BE)] [CRC32C (4 byte, BE)] [ペイロード (JSON)]
+const (
+	recordHeaderSize = 8
+	segmentSuffix    = ".seg"
+	segmentNameWidth = 20
+	maxRecordBytes   = 16 * 1024 * 1024
+)
+
+// crcTable はレコードの検証に用いる CRC32 (Castagnoli) のテーブル
+var crcTable = crc32.MakeTable(crc32.Castagnoli) //nolint:gochecknoglobals // 不変のテーブル
+
+// encodeRecord はペイロードにヘッダーを付与したレコードを返す
+func encodeRecord(payload []byte) []byte {
+	record := make([]byte, recordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(payload))) //nolint:gosec // 長さは maxRecordBytes 以下
+	binary.BigEndian.PutUint32(record[4:8], crc32.Checksum(payload, crcTable))
+	copy(record[recordHeaderSize:], payload)
+
+	return record
+}
+
+// readRecord はレコードを 1 件読み取り、ペイロードとレコード全体のバイト数を返す
+// 末尾が書き込み途中で途切れている場合は io.ErrUnexpectedEOF を返す
+func readRecord(reader *bufio.Reader) ([]byte, int64, error) {
+	header := make([]byte, recordHeaderSize)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, 0, err //nolint:wrapcheck // io.EOF / io.ErrUnexpectedEOF を呼び出し側で判定する
+	}
+
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length > maxRecordBytes {
+		return nil, 0, fmt.Errorf("%w: record length %d exceeds limit", ErrCorruptRecord, length)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+
+	if crc32.Checksum(payload, crcTable) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, 0, ErrCorruptRecord
+	}
+
+	return payload, int64(recordHeaderSize) + int64(length), nil
+}
+
+// segment はセグメントファイルの連番とサイズを保持する構造体
+type segment struct {
+	seq  uint64
+	size int64
+}
+
+// segmentPath はセグメントの連番からファイルパスを返す
+func segmentPath(dir string, seq uint64) string {
+	name := strconv.FormatUint(seq, 10)
+	name = strings.Repeat("0", max(0, segmentNameWidth-len(name))) + name
+
+	return filepath.Join(dir, name+segmentSuffix)
+}
+
+// listSegments はディレクトリ内のセグメントを連番の昇順で返す
+func listSegments(dir string) ([]segment, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read WAL directory: %w", err)
+	}
+
+	segments := make([]segment, 0, len(entries))
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat segment: %w", err)
+		}
+
+		segments = append(segments, segment{seq: seq, size: info.Size()})
+	}
+
+	sort.Slice(segments, func(i, j int) bool { return segments[i].seq < segments[j].seq })
+
+	return segments, nil
+}
+
+// validLength はセグメント先頭から正しく読み取れるレコードの合計バイト数と件数を返す
+// クラッシュにより途中で途切れた末尾のレコードを切り詰める際に使用する
+func validLength(path string, from int64) (int64, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to open segment: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(from, io.SeekStart); err != nil {
+		return 0, 0, fmt.Errorf("failed to seek segment: %w", err)
+	}
+
+	reader := bufio.NewReader(file)
+	offset := from
+	count := 0
+
+	for {
+		_, size, err := readRecord(reader)
+		if err != nil {
+			return offset, count, nil //nolint:nilerr // 読み取れなくなった位置が有効な長さ
+		}
+
+		offset += size
+		count++
+	}
+}